@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hapkiduki/order-go/internal/infrastructure/audit"
+	"github.com/hapkiduki/order-go/internal/infrastructure/config"
+)
+
+// auditUsage describes the audit subcommand.
+const auditUsage = `Usage: api-gateway audit <command> [arguments]
+
+Commands:
+  verify [file]         Check the hash chain of the audit file (audit.path by
+                        default) with the key in audit.hash_key, and print the
+                        number of entries and the last hash
+`
+
+// runAuditCommand runs the audit subcommand.
+//
+// Parameters:
+//   - args: The arguments after "audit"
+//   - stdout: Destination for normal output
+//   - stderr: Destination for errors
+//
+// Returns:
+//   - int: The process exit code
+func runAuditCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, auditUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "verify":
+		return auditVerify(args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, auditUsage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown audit command %q\n\n%s", args[0], auditUsage)
+		return 2
+	}
+}
+
+// auditVerify checks the hash chain of an audit file.
+func auditVerify(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 1 {
+		fmt.Fprint(stderr, auditUsage)
+		return 2
+	}
+
+	// The hash key is a secret: it comes from the configuration
+	// (OPS_AUDIT_HASH_KEY or OPS_AUDIT_HASH_KEY_FILE), never from arguments
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	path := cfg.Audit.Path
+	if len(args) == 1 {
+		path = args[0]
+	}
+	if path == "" {
+		fmt.Fprintln(stderr, "no audit file: pass one or set audit.path")
+		return 2
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer file.Close()
+
+	result, err := audit.Verify(file, cfg.Audit.HashKey.Value())
+	if err != nil {
+		var chainErr *audit.ChainError
+		if errors.As(err, &chainErr) {
+			fmt.Fprintf(stderr, "%s: %v (%d entries intact before it)\n", path, err, result.Entries)
+		} else {
+			fmt.Fprintf(stderr, "%s: %v\n", path, err)
+		}
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s: OK, %d entries, last hash %s\n", path, result.Entries, result.LastHash)
+	return 0
+}
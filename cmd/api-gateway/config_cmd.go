@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hapkiduki/order-go/internal/infrastructure/config"
+)
+
+// configUsage describes the config subcommand.
+const configUsage = `Usage: api-gateway config <command> [arguments]
+
+Commands:
+  print                 Print the effective configuration with secrets redacted,
+                        annotating each key with its source (default, file, env)
+  validate <file>...    Validate config files offline, merged in order
+                        (defaults + files, no env vars)
+  example [-check file] Print a fully commented example config file, or check
+                        that file is up to date with it
+`
+
+// runConfigCommand runs the config subcommand.
+//
+// Parameters:
+//   - args: The arguments after "config"
+//   - stdout: Destination for normal output
+//   - stderr: Destination for errors
+//
+// Returns:
+//   - int: The process exit code
+func runConfigCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, configUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "print":
+		return configPrint(stdout, stderr)
+	case "validate":
+		return configValidate(args[1:], stdout, stderr)
+	case "example":
+		return configExample(args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, configUsage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown config command %q\n\n%s", args[0], configUsage)
+		return 2
+	}
+}
+
+// configPrint prints the effective configuration and its sources.
+func configPrint(stdout, stderr io.Writer) int {
+	settings, cfg, files, err := config.Explain()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	merged := "none"
+	if len(files) > 0 {
+		merged = strings.Join(files, ", ")
+	}
+	fmt.Fprintf(stdout, "# Effective configuration (secrets redacted)\n# Config files: %s\n", merged)
+	if err := config.WriteEffective(stdout, settings); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	// The server would refuse to start with this configuration
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(stderr, "\n%v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// configValidate validates config files offline.
+func configValidate(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, configUsage)
+		return 2
+	}
+
+	files := strings.Join(args, " + ")
+	if _, err := config.LoadFile(args...); err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", files, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s: OK\n", files)
+	return 0
+}
+
+// configExample prints the example config file or checks a file against it.
+func configExample(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("config example", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	check := flags.String("check", "", "fail if `file` differs from the generated example")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	var example bytes.Buffer
+	if err := config.WriteExample(&example); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if *check == "" {
+		stdout.Write(example.Bytes())
+		return 0
+	}
+
+	current, err := os.ReadFile(*check)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if !bytes.Equal(current, example.Bytes()) {
+		fmt.Fprintf(stderr, "%s is out of date: run \"make config-example\"\n", *check)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s: up to date\n", *check)
+	return 0
+}
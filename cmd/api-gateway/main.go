@@ -8,22 +8,27 @@
 //   - VI. Processes: Stateless processes
 //   - VII. Port Binding: Self-contained HTTP server
 //   - IX. Disposability: Graceful shutdown
-//   - XI. Logs: Structured logging to stdout
+//   - XI. Logs: Structured logging to stdout (optionally to rotated files)
 //
 // Usage:
 //
-//	go run cmd/api-gateway/main.go
+//	go run ./cmd/api-gateway
+//	go run ./cmd/api-gateway config print|validate|example
 //
 // Environment Variables:
 //
 //	OPS_ENVIRONMENT - Deployment environment (development, staging, production)
 //	OPS_SERVER_PORT - HTTP server port (default: 8080)
+//	OPS_DATABASE_DSN - PostgreSQL connection string (in-memory storage if empty)
+//	OPS_RABBITMQ_URL - RabbitMQ URL for domain events (in-process only if empty)
+//
+// Secrets can be read from files instead, e.g. OPS_DATABASE_DSN_FILE=/run/secrets/dsn.
 package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -34,9 +39,26 @@ import (
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/application/service"
+	"github.com/hapkiduki/order-go/internal/infrastructure/audit"
 	"github.com/hapkiduki/order-go/internal/infrastructure/config"
+	"github.com/hapkiduki/order-go/internal/infrastructure/featureflags"
+	"github.com/hapkiduki/order-go/internal/infrastructure/health"
+	"github.com/hapkiduki/order-go/internal/infrastructure/logging"
+	"github.com/hapkiduki/order-go/internal/infrastructure/messaging"
+	messagingmemory "github.com/hapkiduki/order-go/internal/infrastructure/messaging/memory"
+	"github.com/hapkiduki/order-go/internal/infrastructure/messaging/rabbitmq"
+	"github.com/hapkiduki/order-go/internal/infrastructure/metrics"
+	"github.com/hapkiduki/order-go/internal/infrastructure/persistence/memory"
+	"github.com/hapkiduki/order-go/internal/infrastructure/persistence/postgres"
+	redisstore "github.com/hapkiduki/order-go/internal/infrastructure/persistence/redis"
+	"github.com/hapkiduki/order-go/internal/infrastructure/tracing"
+	"github.com/hapkiduki/order-go/internal/interfaces/http/handler"
 	"github.com/hapkiduki/order-go/internal/interfaces/http/middleware"
+	"github.com/hapkiduki/order-go/internal/interfaces/http/response"
+	"github.com/hapkiduki/order-go/migrations"
 	"github.com/hapkiduki/order-go/pkg/logger"
+	goredis "github.com/redis/go-redis/v9"
 )
 
 // version is set at build time via ldflags
@@ -46,17 +68,57 @@ var version = "dev"
 var startTime = time.Now()
 
 func main() {
+	// Subcommands that inspect the configuration or the audit trail without
+	// starting the server
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "audit" {
+		os.Exit(runAuditCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	// Load configuration
 	cfg := config.MustLoad()
 
 	// Initialize logger
+	logSinks := make([]logger.SinkConfig, 0, len(cfg.Log.Sinks))
+	for _, sink := range cfg.Log.Sinks {
+		logSinks = append(logSinks, logger.SinkConfig{
+			Output: sink.Output,
+			Format: sink.Format,
+			Level:  sink.Level,
+		})
+	}
 	log := logger.MustNew(logger.Config{
-		Level:       cfg.Log.Level,
-		Format:      cfg.Log.Format,
+		Level:  cfg.Log.Level,
+		Format: cfg.Log.Format,
+		Output: cfg.Log.Output,
+		Rotation: logger.RotationConfig{
+			MaxSizeMB:  cfg.Log.MaxSizeMB,
+			Interval:   cfg.Log.RotationInterval,
+			MaxBackups: cfg.Log.MaxBackups,
+			MaxAge:     cfg.Log.MaxAge,
+		},
+		Sinks: logSinks,
+		Sampling: &logger.SamplingConfig{
+			Initial:         cfg.Log.SamplingInitial,
+			Thereafter:      cfg.Log.SamplingThereafter,
+			Tick:            cfg.Log.SamplingTick,
+			SummaryInterval: cfg.Log.SamplingSummaryInterval,
+		},
+		Redaction: &logger.RedactionConfig{
+			Keys:     cfg.Log.RedactKeys,
+			Patterns: cfg.Log.RedactPatterns,
+			HashKey:  cfg.Log.RedactHashKey.Value(),
+		},
 		Development: cfg.App.Environment == "development",
 	})
 	defer log.Sync()
 
+	// Libraries logging with log/slog (and the standard log package) write
+	// to the same stream
+	slog.SetDefault(log.Slog())
+
 	log.Info("Starting Order Processing System (Monolith)",
 		"version", version,
 		"environment", cfg.App.Environment,
@@ -67,7 +129,174 @@ func main() {
 	defer stop()
 
 	// Create a logger adapter that implements port.Logger
-	logAdapter := &loggerAdapter{log}
+	logAdapter := logging.New(log)
+
+	// Live configuration reload: subscribers below apply the settings that
+	// can change without a restart (log level, rate limits, CORS origins,
+	// feature flags)
+	configWatcher, err := config.Watch(cfg, logging.New(log.Named("config").With("component", "config")))
+	if err != nil {
+		log.Fatal("Failed to watch configuration", "error", err)
+	}
+	defer configWatcher.Close()
+	// Only a changed log.level is applied, so a reload does not undo a level
+	// set through the admin endpoint
+	logLevel := cfg.Log.Level
+	configWatcher.Subscribe(func(c *config.Config) {
+		if c.Log.Level == logLevel {
+			return
+		}
+		logLevel = c.Log.Level
+		if err := log.SetLevel(c.Log.Level); err != nil {
+			log.Error("Failed to apply log level", "level", c.Log.Level, "error", err)
+		}
+	})
+
+	// Dependency health checks used by the readiness probe
+	healthRegistry := health.NewRegistry()
+
+	// Persistence: PostgreSQL when a DSN is configured, in-memory otherwise.
+	// Domain events are written to the outbox of the same store.
+	memoryOutbox := memory.NewOutbox()
+	var orderRepo port.OrderRepository = memory.NewOrderRepository(memoryOutbox)
+	var outbox port.OutboxStore = memoryOutbox
+	if cfg.Database.DSN != "" {
+		pool, err := postgres.NewPool(ctx, cfg.Database)
+		if err != nil {
+			log.Fatal("Failed to connect to database", "error", err)
+		}
+		defer pool.Close()
+
+		if cfg.Database.AutoMigrate {
+			applied, err := postgres.Migrate(ctx, pool, migrations.FS)
+			if err != nil {
+				log.Fatal("Failed to apply database migrations", "error", err)
+			}
+			log.Info("Database migrations applied", "versions", applied)
+		}
+
+		orderRepo = postgres.NewOrderRepository(pool, cfg.Database.QueryTimeout)
+		outbox = postgres.NewOutbox(pool, cfg.Database.QueryTimeout)
+		healthRegistry.Register(postgres.NewHealthChecker(pool), health.Critical())
+	} else {
+		log.Warn("No database DSN configured, using in-memory order storage")
+	}
+
+	// Redis (optional): shared state across replicas
+	var redisClient *goredis.Client
+	if cfg.Redis.Addr != "" {
+		client, err := redisstore.NewClient(ctx, cfg.Redis)
+		if err != nil {
+			log.Fatal("Failed to connect to Redis", "error", err)
+		}
+		defer client.Close()
+		redisClient = client
+		healthRegistry.Register(redisstore.NewHealthChecker(redisClient), health.Critical())
+	}
+
+	// Idempotency-Key storage
+	var idempotencyStore port.IdempotencyStore = memory.NewIdempotencyStore()
+	if cfg.Idempotency.Store == "redis" {
+		if redisClient == nil {
+			log.Fatal("Idempotency store 'redis' requires redis.addr to be set")
+		}
+		idempotencyStore = redisstore.NewIdempotencyStore(redisClient)
+	}
+
+	// Rate limiter token buckets
+	var rateLimitStore port.RateLimitStore
+	if cfg.RateLimit.Store == "redis" {
+		if redisClient == nil {
+			log.Fatal("Rate limit store 'redis' requires redis.addr to be set")
+		}
+		rateLimitStore = redisstore.NewRateLimitStore(redisClient)
+	} else {
+		memoryRateLimitStore := memory.NewRateLimitStore(cfg.RateLimit.CleanupInterval, cfg.RateLimit.InactiveTTL)
+		defer memoryRateLimitStore.Close()
+		rateLimitStore = memoryRateLimitStore
+	}
+
+	// Feature flags, evaluated per request against the FlagContext middleware
+	flags, err := featureflags.NewProvider(cfg.FeatureFlags.Flags)
+	if err != nil {
+		log.Fatal("Failed to load feature flags", "error", err)
+	}
+	configWatcher.Subscribe(func(c *config.Config) {
+		if err := flags.Set(c.FeatureFlags.Flags); err != nil {
+			log.Error("Failed to apply feature flags", "error", err)
+		}
+	})
+
+	// Audit trail (optional): a hash-chained file, separate from the logs
+	var auditLogger port.AuditLogger
+	if cfg.Audit.Path != "" {
+		fileAudit, err := audit.Open(cfg.Audit.Path, cfg.Audit.HashKey.Value())
+		if err != nil {
+			log.Fatal("Failed to open audit trail", "error", err)
+		}
+		defer fileAudit.Close()
+		if torn := fileAudit.Torn(); torn > 0 {
+			log.Warn("Removed an incomplete audit entry left by a crash",
+				"path", cfg.Audit.Path+audit.TornSuffix,
+				"bytes", torn,
+			)
+		}
+		auditLogger = fileAudit
+	} else {
+		log.Warn("No audit path configured, order changes and admin actions are not audited")
+	}
+
+	// Application services and their HTTP handlers
+	orderService := service.NewOrderService(orderRepo, logAdapter, auditLogger)
+	orderHandler := handler.NewOrderHandler(orderService, logAdapter, cfg.Server.MaxRequestSize)
+
+	// Metrics (Prometheus); nil disables recording
+	var metricsRecorder port.Metrics
+	var promMetrics *metrics.Prometheus
+	if cfg.Metrics.Enabled {
+		promMetrics = metrics.NewPrometheus(cfg.Metrics.Namespace)
+		metricsRecorder = promMetrics
+	}
+
+	// Domain events: RabbitMQ when a URL is configured, logged and dropped otherwise
+	var eventPublisher port.EventPublisher = messagingmemory.NewLogPublisher(logging.New(log.Named("outbox")))
+	if cfg.RabbitMQ.URL != "" {
+		publisher, err := rabbitmq.NewPublisher(cfg.RabbitMQ)
+		if err != nil {
+			log.Fatal("Failed to connect to RabbitMQ", "error", err)
+		}
+		defer publisher.Close()
+		eventPublisher = publisher
+		healthRegistry.Register(rabbitmq.NewHealthChecker(publisher))
+	} else {
+		log.Warn("No RabbitMQ URL configured, domain events are not delivered to other services")
+	}
+
+	// Outbox relay: publishes stored domain events until shutdown
+	relay := messaging.NewRelay(outbox, eventPublisher, messaging.RelayConfig{
+		PollInterval: cfg.Outbox.PollInterval,
+		BatchSize:    cfg.Outbox.BatchSize,
+		Lease:        cfg.Outbox.Lease,
+		MinBackoff:   cfg.Outbox.MinBackoff,
+		MaxBackoff:   cfg.Outbox.MaxBackoff,
+		Metrics:      metricsRecorder,
+	}, logging.New(log.Named("outbox").With("component", "outbox_relay")))
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relay.Run(ctx)
+	}()
+
+	// Tracing (OpenTelemetry); the W3C propagator is installed even when disabled
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing, tracing.ServiceInfo{
+		Name:        cfg.App.Name,
+		Version:     version,
+		Environment: cfg.App.Environment,
+	})
+	if err != nil {
+		log.Fatal("Failed to initialize tracing", "error", err)
+	}
+	tracer := tracing.NewTracer()
 
 	// Create Chi router
 	r := chi.NewRouter()
@@ -83,50 +312,133 @@ func main() {
 	// 2. Request ID generation/propagation
 	r.Use(middleware.RequestID)
 
-	// 3. Logging (after Request ID so it's included in logs)
-	r.Use(middleware.Logger(logAdapter))
+	// 3. Feature flag evaluation attributes (tenant, client IP, environment)
+	r.Use(middleware.FlagContext(cfg.App.Environment, cfg.FeatureFlags.TenantHeader))
+
+	// 4. Tracing (joins the caller's trace from traceparent/tracestate)
+	r.Use(middleware.Tracing(tracer))
+
+	// 5. Logging (after Request ID and Tracing so their IDs are included in logs)
+	r.Use(middleware.LoggerWithPolicy(logAdapter, middleware.RequestLogPolicy{
+		SkipPaths:       cfg.RequestLog.SkipPaths,
+		SamplePaths:     cfg.RequestLog.SamplePaths,
+		SampleRate:      cfg.RequestLog.SampleRate,
+		SlowThreshold:   cfg.RequestLog.SlowThreshold,
+		SummaryInterval: cfg.RequestLog.SummaryInterval,
+	}))
+
+	// 6. RED metrics (before Recoverer so recovered panics count as 500s)
+	if metricsRecorder != nil {
+		r.Use(middleware.Metrics(metricsRecorder))
+	}
+
+	// 7. Panic recovery
+	r.Use(middleware.Recoverer(logAdapter, metricsRecorder))
 
-	// 4. Panic recovery
-	r.Use(middleware.Recoverer(logAdapter))
+	// 8. Request timeout
+	r.Use(chimiddleware.Timeout(cfg.Server.RequestTimeout))
 
-	// 5. Request timeout
-	r.Use(chimiddleware.Timeout(30 * time.Second))
+	// ============================================================================
+	// Probes and metrics
+	// ============================================================================
+	// Outside the API middleware below: probes and scrapers must never be rate
+	// limited, and /metrics keeps the Prometheus content type.
+
+	// Health check endpoints (no auth required)
+	r.Get("/health", healthHandler())
+	r.Get("/ready", readinessHandler(healthRegistry))
+
+	// Prometheus scrape endpoint
+	if promMetrics != nil {
+		r.Method(http.MethodGet, cfg.Metrics.Path, promMetrics.Handler())
+	}
 
-	// 6. CORS
-	r.Use(cors.Handler(cors.Options{
+	// ============================================================================
+	// API middleware (every other route, including 404 and 405 responses)
+	// ============================================================================
+
+	api := chi.NewRouter()
+
+	// 9. CORS
+	corsMiddleware := middleware.NewCORS(cors.Options{
 		AllowedOrigins:   cfg.Server.CORSAllowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
-		ExposedHeaders:   []string{"X-Request-ID", "X-API-Version"},
-		AllowCredentials: true,
-		MaxAge:           300,
-	}))
+		AllowedMethods:   cfg.CORS.AllowedMethods,
+		AllowedHeaders:   cfg.CORS.AllowedHeaders,
+		ExposedHeaders:   cfg.CORS.ExposedHeaders,
+		AllowCredentials: cfg.CORS.AllowCredentials,
+		MaxAge:           int(cfg.CORS.MaxAge.Seconds()),
+	})
+	configWatcher.Subscribe(func(c *config.Config) {
+		corsMiddleware.SetAllowedOrigins(c.Server.CORSAllowedOrigins)
+	})
+	api.Use(corsMiddleware.Handler)
 
-	// 7. Rate limiting
-	r.Use(middleware.RateLimiter(middleware.DefaultRateLimiterConfig()))
+	// 10. Rate limiting
+	rateLimits := middleware.NewRateLimits(cfg.RateLimit.RequestsPerSecond, cfg.RateLimit.Burst)
+	configWatcher.Subscribe(func(c *config.Config) {
+		rateLimits.Set(c.RateLimit.RequestsPerSecond, c.RateLimit.Burst)
+	})
+	rateLimiterConfig := middleware.DefaultRateLimiterConfig()
+	rateLimiterConfig.Limits = rateLimits
+	rateLimiterConfig.Store = rateLimitStore
+	rateLimiterConfig.Logger = logAdapter
+	rateLimiterConfig.Metrics = metricsRecorder
+	api.Use(middleware.RateLimiter(rateLimiterConfig))
+
+	// 11. Security headers
+	api.Use(middleware.SecureHeadersWithConfig(middleware.SecureHeadersConfig{
+		ContentTypeOptions:      cfg.SecurityHeaders.ContentTypeOptions,
+		FrameOptions:            cfg.SecurityHeaders.FrameOptions,
+		ContentSecurityPolicy:   cfg.SecurityHeaders.ContentSecurityPolicy,
+		ReferrerPolicy:          cfg.SecurityHeaders.ReferrerPolicy,
+		StrictTransportSecurity: cfg.SecurityHeaders.StrictTransportSecurity,
+	}))
 
-	// 8. Security headers
-	r.Use(middleware.SecureHeaders)
+	// 12. API version header
+	api.Use(middleware.APIVersion(version))
 
-	// 9. API version header
-	r.Use(middleware.APIVersion(version))
+	// 13. Content-Type enforcement
+	api.Use(middleware.ContentTypeJSON)
 
-	// 10. Content-Type enforcement
-	r.Use(middleware.ContentTypeJSON)
+	// 14. Idempotency-Key handling for safe POST/PATCH retries
+	if cfg.Idempotency.Enabled {
+		api.Use(middleware.Idempotency(middleware.IdempotencyConfig{
+			Store:        idempotencyStore,
+			TTL:          cfg.Idempotency.TTL,
+			LockTTL:      cfg.Idempotency.LockTTL,
+			MaxBodyBytes: cfg.Server.MaxRequestSize,
+		}, logAdapter))
+	}
 
 	// ============================================================================
 	// Routes
 	// ============================================================================
 
-	// Health check endpoints (no auth required)
-	r.Get("/health", healthHandler())
-	//r.Get("/ready", readinessHandler())
+	// Operational endpoints, protected by the admin token. Its holder is the
+	// "admin" principal in the logs and the audit trail.
+	if token := cfg.Admin.Token.Value(); token != "" {
+		api.Route("/admin", func(r chi.Router) {
+			r.Use(middleware.RequireBearerToken(token, "admin"))
+			r.Mount("/feature-flags", handler.NewFeatureFlagHandler(flags).Routes())
+			r.Mount("/log-level", handler.NewLogLevelHandler(log, logAdapter, auditLogger).Routes())
+		})
+	} else {
+		log.Info("No admin token configured, admin endpoints are disabled")
+	}
+
+	// Versioned API
+	api.Route("/api/v1", func(r chi.Router) {
+		r.Mount("/orders", orderHandler.Routes())
+	})
 
 	// 404 handler
-	r.NotFound(notFoundHandler)
+	api.NotFound(notFoundHandler)
 
 	// 405 handler
-	r.MethodNotAllowed(methodNotAllowedHandler)
+	api.MethodNotAllowed(methodNotAllowedHandler)
+
+	// Every path but the operational endpoints above
+	r.Mount("/", api)
 
 	// ============================================================================
 	// HTTP server
@@ -162,6 +474,18 @@ func main() {
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		log.Error("Server forced to shutdown", "error", err)
 	}
+
+	// Let the outbox relay finish its current batch
+	select {
+	case <-relayDone:
+	case <-shutdownCtx.Done():
+		log.Warn("Outbox relay did not stop before the shutdown timeout")
+	}
+
+	// Flush pending spans
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Error("Failed to flush traces", "error", err)
+	}
 	log.Info("Server shutdown complete")
 
 }
@@ -169,9 +493,7 @@ func main() {
 // healthHandler returns the health check handler.
 func healthHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]interface{}{
+		response.JSON(w, http.StatusOK, map[string]interface{}{
 			"status":  "healthy",
 			"version": version,
 			"uptime":  time.Since(startTime).String(),
@@ -180,72 +502,32 @@ func healthHandler() http.HandlerFunc {
 }
 
 // readinessHandler returns the readiness check handler.
-func readinessHandler() http.HandlerFunc {
-	// TODO: verify the database connection
-	panic("unimplemented")
+// It runs every registered dependency check and returns 503 when any critical check is down.
+//
+// Parameters:
+//   - registry: The dependency health check registry
+//
+// Returns:
+//   - http.HandlerFunc: The readiness handler
+func readinessHandler(registry *health.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := registry.Check(r.Context())
+
+		if !report.Ready() {
+			response.ErrorWithData(w, r, response.NotReady(), report)
+			return
+		}
+
+		response.Success(w, r, http.StatusOK, report)
+	}
 }
 
 // notFoundHandler handles 404 responses.
 func notFoundHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotFound)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"success": false,
-		"error": map[string]string{
-			"code":    "NOT_FOUND",
-			"message": "The requested resource was not found",
-		},
-	})
+	response.Error(w, r, response.NotFound("The requested resource was not found"))
 }
 
 // methodNotAllowedHandler handles 405 responses.
 func methodNotAllowedHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusMethodNotAllowed)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"success": false,
-		"error": map[string]string{
-			"code":    "METHOD_NOT_ALLOWED",
-			"message": "The requested method is not allowed for this resource",
-		},
-	})
-}
-
-// ============================================================================
-// Adapters to implement port interfaces
-// ============================================================================
-
-// loggerAdapter adapts the logger.Logger to the port.Logger interface.
-type loggerAdapter struct {
-	*logger.Logger
-}
-
-// Debug implements port.Logger.
-func (l *loggerAdapter) Debug(msg string, keysAndValues ...any) {
-	l.Logger.Debug(msg, keysAndValues...)
-}
-
-// Info implements port.Logger.
-func (l *loggerAdapter) Info(msg string, keysAndValues ...any) {
-	l.Logger.Info(msg, keysAndValues...)
-}
-
-// Warn implements port.Logger.
-func (l *loggerAdapter) Warn(msg string, keysAndValues ...any) {
-	l.Logger.Warn(msg, keysAndValues...)
-}
-
-// Error implements port.Logger.
-func (l *loggerAdapter) Error(msg string, keysAndValues ...any) {
-	l.Logger.Error(msg, keysAndValues...)
-}
-
-// With implements port.Logger.
-func (l *loggerAdapter) With(keysAndValues ...any) port.Logger {
-	return &loggerAdapter{l.Logger.With(keysAndValues...)}
-}
-
-// WithContext implements port.Logger.
-func (l *loggerAdapter) WithContext(ctx context.Context) port.Logger {
-	return &loggerAdapter{l.Logger.WithContext(ctx)}
+	response.Error(w, r, response.MethodNotAllowed())
 }
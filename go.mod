@@ -3,42 +3,92 @@ module github.com/hapkiduki/order-go
 go 1.25.1
 
 require (
+	// Configuration management (12-Factor: III. Config)
+	github.com/fsnotify/fsnotify v1.9.0
 	// Router - Lightweight, idiomatic and composable router
 	github.com/go-chi/chi/v5 v5.2.3
 
 	// Chi middleware
 	github.com/go-chi/cors v1.2.2
-	github.com/go-chi/render v1.0.3
 
 	// UUID generation
 	github.com/google/uuid v1.6.0
 
-	// Configuration management (12-Factor: III. Config)
+	// PostgreSQL driver and connection pool
+	github.com/jackc/pgx/v5 v5.7.5
+
+	// Metrics (Prometheus exposition)
+	github.com/prometheus/client_golang v1.22.0
+
+	// Message broker (RabbitMQ, AMQP 0-9-1)
+	github.com/rabbitmq/amqp091-go v1.10.0
+
+	// Redis client (shared state across replicas)
+	github.com/redis/go-redis/v9 v9.7.3
 	github.com/spf13/viper v1.21.0
 
-	// Testing
-	github.com/stretchr/testify v1.11.1
+	// Tracing (OpenTelemetry, W3C trace context)
+	go.opentelemetry.io/otel v1.37.0
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.37.0
+	go.opentelemetry.io/otel/exporters/stdout/stdouttrace v1.37.0
+	go.opentelemetry.io/otel/sdk v1.37.0
+	go.opentelemetry.io/otel/trace v1.37.0
 
 	// Logging (12-Factor: XI. Logs)
 	go.uber.org/zap v1.27.1
+	go.yaml.in/yaml/v3 v3.0.4
 
 	// Rate limiting
 	golang.org/x/time v0.14.0
 )
 
+require github.com/spf13/afero v1.15.0
+
+// Testing
+require (
+	github.com/alicebob/miniredis/v2 v2.34.0
+	github.com/stretchr/testify v1.11.1
+)
+
 require (
-	github.com/ajg/form v1.5.1 // indirect
-	github.com/fsnotify/fsnotify v1.9.0 // indirect
+	github.com/alicebob/gopher-json v0.0.0-20230218143504-906a9b012302 // indirect
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cenkalti/backoff/v5 v5.0.2 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/go-viper/mapstructure/v2 v2.4.0 // indirect
+	github.com/grpc-ecosystem/grpc-gateway/v2 v2.27.1 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	github.com/jackc/puddle/v2 v2.2.2 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
 	github.com/pelletier/go-toml/v2 v2.2.4 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/prometheus/client_model v0.6.1 // indirect
+	github.com/prometheus/common v0.62.0 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
 	github.com/sagikazarmark/locafero v0.11.0 // indirect
 	github.com/sourcegraph/conc v0.3.1-0.20240121214520-5f936abd7ae8 // indirect
-	github.com/spf13/afero v1.15.0 // indirect
 	github.com/spf13/cast v1.10.0 // indirect
 	github.com/spf13/pflag v1.0.10 // indirect
 	github.com/subosito/gotenv v1.6.0 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace v1.37.0 // indirect
+	go.opentelemetry.io/otel/metric v1.37.0 // indirect
+	go.opentelemetry.io/proto/otlp v1.7.0 // indirect
 	go.uber.org/multierr v1.10.0 // indirect
-	go.yaml.in/yaml/v3 v3.0.4 // indirect
-	golang.org/x/sys v0.29.0 // indirect
+	golang.org/x/crypto v0.39.0 // indirect
+	golang.org/x/net v0.41.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
 	golang.org/x/text v0.28.0 // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20250603155806-513f23925822 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250603155806-513f23925822 // indirect
+	google.golang.org/grpc v1.73.0 // indirect
+	google.golang.org/protobuf v1.36.6 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -14,7 +14,10 @@ package port
 
 import (
 	"context"
+	"errors"
 	"time"
+
+	"github.com/hapkiduki/order-go/internal/domain/order"
 )
 
 // Logger defines the interface for structured logging.
@@ -53,6 +56,11 @@ type Metrics interface {
 	// Gauge sets a gauge metric value.
 	Gauge(name string, value float64, tags map[string]string)
 
+	// GaugeAdd adds delta (possibly negative) to a gauge metric. Use it for
+	// values updated concurrently, such as in-flight counts: setting a
+	// snapshot from several goroutines can publish them out of order.
+	GaugeAdd(name string, delta float64, tags map[string]string)
+
 	// Histogram records a value in a histogram.
 	Histogram(name string, value float64, tags map[string]string)
 
@@ -89,3 +97,322 @@ type Span interface {
 	// AddEvent adds an event to the span.
 	AddEvent(name string, attributes map[string]interface{})
 }
+
+// HealthStatus is the result of a dependency health check.
+type HealthStatus string
+
+const (
+	// HealthStatusOK means the dependency is fully operational.
+	HealthStatusOK HealthStatus = "ok"
+
+	// HealthStatusDegraded means the dependency works with reduced capacity,
+	// or a non-critical dependency is unavailable.
+	HealthStatusDegraded HealthStatus = "degraded"
+
+	// HealthStatusDown means the dependency is unavailable.
+	HealthStatusDown HealthStatus = "down"
+)
+
+// ErrDegraded can be returned (or wrapped) by a HealthChecker to report
+// that a dependency is reachable but not fully healthy.
+var ErrDegraded = errors.New("dependency degraded")
+
+// HealthChecker defines the interface for checking a single dependency
+// (database, cache, message broker, etc.).
+//
+// Example usage:
+//
+//	registry.Register(postgresChecker, health.Critical())
+type HealthChecker interface {
+	// Name returns a stable, unique identifier for the dependency (e.g., "postgres").
+	Name() string
+
+	// Check verifies that the dependency is available.
+	// It must honour ctx cancellation; a nil error means healthy.
+	Check(ctx context.Context) error
+}
+
+// OrderFilter narrows down the orders returned by OrderRepository.List.
+// Zero values mean "no filter".
+type OrderFilter struct {
+	// CustomerID restricts results to a single customer
+	CustomerID string
+
+	// Status restricts results to orders in the given status
+	Status order.Status
+
+	// Limit is the maximum number of orders to return
+	Limit int
+
+	// Offset is the number of orders to skip
+	Offset int
+}
+
+// OrderRepository defines the interface for persisting Order aggregates.
+// Implementation may use PostgreSQL, an in-memory map, etc.
+//
+// Implementations must return order.ErrNotFound when an order does not exist.
+//
+// Saves use optimistic locking: Save fails with order.ErrConcurrentModification
+// when the stored version is not the one the order was loaded at (or when a
+// new order's ID is already taken), and bumps the version of o on success.
+type OrderRepository interface {
+	// Save inserts a new order or updates an existing one, including its line items.
+	Save(ctx context.Context, o *order.Order) error
+
+	// FindByID returns the order with the given ID.
+	FindByID(ctx context.Context, id string) (*order.Order, error)
+
+	// List returns the orders matching the filter, newest first,
+	// together with the total number of matching orders (ignoring Limit/Offset).
+	List(ctx context.Context, filter OrderFilter) ([]*order.Order, int, error)
+}
+
+// IdempotencyRecord is the stored outcome of a request made with an Idempotency-Key.
+// A record that is not Completed represents a request that is still in flight.
+type IdempotencyRecord struct {
+	// Fingerprint identifies the request payload (method, path and body hash)
+	Fingerprint string `json:"fingerprint"`
+
+	// Completed reports whether the response has been stored
+	Completed bool `json:"completed"`
+
+	// StatusCode is the stored HTTP status code
+	StatusCode int `json:"status_code,omitempty"`
+
+	// Header contains the stored response headers
+	Header map[string][]string `json:"header,omitempty"`
+
+	// Body is the stored response body
+	Body []byte `json:"body,omitempty"`
+
+	// CreatedAt is when the key was first seen
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IdempotencyStore defines the interface for storing idempotent request outcomes.
+// Implementation may use an in-memory map or Redis (shared across replicas).
+type IdempotencyStore interface {
+	// Reserve atomically claims key for a new in-flight request.
+	// If the key is already known, it returns the existing record and false.
+	// The reservation expires after lockTTL so a crashed request does not block retries forever.
+	Reserve(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (*IdempotencyRecord, bool, error)
+
+	// Complete stores the final response for key, replacing the reservation.
+	Complete(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error
+
+	// Release removes an unfinished reservation so the request can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// RateLimit describes a token bucket: Rate tokens are added per second, up to Burst.
+type RateLimit struct {
+	// Rate is the sustained number of requests allowed per second
+	Rate float64
+
+	// Burst is the maximum number of requests allowed at once
+	Burst int
+}
+
+// RateLimitResult is the outcome of a rate limit check.
+type RateLimitResult struct {
+	// Allowed reports whether the request may proceed
+	Allowed bool
+
+	// RetryAfter is how long the client should wait before retrying (zero if allowed)
+	RetryAfter time.Duration
+}
+
+// RateLimitStore defines the interface for rate limiter state.
+// Implementation may use an in-memory map (per process) or Redis (shared across replicas).
+type RateLimitStore interface {
+	// Allow consumes one token from the bucket identified by key, if available.
+	// The limit is passed on every call so it can change at runtime.
+	Allow(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error)
+}
+
+// Message is a domain event serialized for delivery to other services.
+type Message struct {
+	// ID uniquely identifies the message (consumers use it for deduplication)
+	ID string
+
+	// Type is the event name (e.g., "order.paid")
+	Type string
+
+	// AggregateType is the kind of aggregate that emitted the event (e.g., "order")
+	AggregateType string
+
+	// AggregateID identifies the aggregate; events of one aggregate are delivered in order
+	AggregateID string
+
+	// Payload is the JSON-encoded event body
+	Payload []byte
+
+	// OccurredAt is when the event happened
+	OccurredAt time.Time
+}
+
+// EventPublisher defines the interface for publishing domain events to a message broker.
+// Implementation may use RabbitMQ or an in-memory recorder (tests).
+// Publish must only return nil once the broker has accepted the message.
+type EventPublisher interface {
+	// Publish delivers a single message.
+	Publish(ctx context.Context, msg Message) error
+}
+
+// OutboxMessage is a message waiting in the transactional outbox.
+type OutboxMessage struct {
+	Message
+
+	// Attempts is the number of failed publish attempts so far
+	Attempts int
+}
+
+// OutboxStore defines the interface used by the outbox relay.
+// Messages are written by the repositories in the same transaction as the
+// aggregate change that produced them.
+type OutboxStore interface {
+	// ClaimPending leases up to limit messages that are due for publishing.
+	// Only the oldest unpublished message of each aggregate is returned, so the
+	// events of one aggregate are published strictly in order. Claimed messages
+	// are hidden from other relays until lease expires.
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]OutboxMessage, error)
+
+	// MarkPublished records that the message was delivered.
+	MarkPublished(ctx context.Context, id string) error
+
+	// MarkFailed records a failed attempt and schedules the next one.
+	MarkFailed(ctx context.Context, id string, nextAttemptAt time.Time, cause error) error
+}
+
+// FlagContext holds the attributes a feature flag is evaluated against.
+type FlagContext struct {
+	// TenantID identifies the tenant making the request
+	TenantID string
+
+	// UserID identifies the user making the request
+	UserID string
+
+	// ClientIP is the real client IP
+	ClientIP string
+
+	// Environment is the deployment environment (e.g., "production")
+	Environment string
+}
+
+// flagContextKey is the context key for the FlagContext.
+type flagContextKey struct{}
+
+// WithFlagContext returns a copy of ctx carrying fc.
+//
+// Parameters:
+//   - ctx: The parent context
+//   - fc: The flag evaluation attributes
+//
+// Returns:
+//   - context.Context: The context carrying fc
+func WithFlagContext(ctx context.Context, fc FlagContext) context.Context {
+	return context.WithValue(ctx, flagContextKey{}, fc)
+}
+
+// FlagContextFrom returns the FlagContext carried by ctx, or the zero value.
+//
+// Parameters:
+//   - ctx: The context
+//
+// Returns:
+//   - FlagContext: The flag evaluation attributes
+func FlagContextFrom(ctx context.Context) FlagContext {
+	fc, _ := ctx.Value(flagContextKey{}).(FlagContext)
+	return fc
+}
+
+// Reasons reported in FlagEvaluation.Reason.
+const (
+	// FlagReasonNotFound means the flag is not defined (it is off)
+	FlagReasonNotFound = "not_found"
+
+	// FlagReasonDisabled means the flag is switched off for everyone
+	FlagReasonDisabled = "disabled"
+
+	// FlagReasonEnvironment means the flag is not enabled in this environment
+	FlagReasonEnvironment = "environment"
+
+	// FlagReasonTarget means the tenant, user or client IP is explicitly targeted
+	FlagReasonTarget = "target"
+
+	// FlagReasonRollout means the result comes from the percentage rollout
+	FlagReasonRollout = "rollout"
+
+	// FlagReasonDefault means the flag is on for everyone
+	FlagReasonDefault = "default"
+)
+
+// FlagEvaluation is the result of evaluating a feature flag.
+type FlagEvaluation struct {
+	// Key is the flag name
+	Key string
+
+	// Description explains what the flag controls
+	Description string
+
+	// Enabled reports whether the flag is on
+	Enabled bool
+
+	// Variant is the selected variant of a multivariate flag ("" for boolean flags or when off)
+	Variant string
+
+	// Reason explains the result (one of the FlagReason constants)
+	Reason string
+}
+
+// FeatureFlags defines the interface for evaluating feature flags.
+// Flags are evaluated against the FlagContext carried by the request context.
+// Unknown flags are off, so code can ship dark before its flag is configured.
+type FeatureFlags interface {
+	// IsEnabled reports whether a flag is on.
+	IsEnabled(ctx context.Context, key string) bool
+
+	// Variant returns the variant of a multivariate flag, or fallback when the
+	// flag is off or has no variants.
+	Variant(ctx context.Context, key, fallback string) string
+
+	// Evaluate evaluates a flag against explicit attributes.
+	Evaluate(key string, fc FlagContext) FlagEvaluation
+}
+
+// AuditEntry is a record of a change that compliance must be able to trace
+// (order state changes, refunds, admin actions).
+type AuditEntry struct {
+	// Actor is who made the change (user ID, "admin", "system"); filled from
+	// the user ID of the context when empty, which the authentication
+	// middleware sets to the principal. Unauthenticated requests (e.g., the
+	// order API) are recorded as "anonymous".
+	Actor string
+
+	// Action is what was done (e.g., "order.refunded", "admin.log_level.changed")
+	Action string
+
+	// Resource identifies what was changed (e.g., "order/8f14e45f")
+	Resource string
+
+	// Before is the state before the change (nil when created)
+	Before any
+
+	// After is the state after the change (nil when deleted)
+	After any
+
+	// RequestID is the request that made the change; filled from the context when empty
+	RequestID string
+
+	// ClientIP is the real client IP (see middleware.GetRealIP); filled from
+	// the context when empty
+	ClientIP string
+}
+
+// AuditLogger defines the interface for recording audit entries.
+// Entries are written to a stream separate from the application logs.
+type AuditLogger interface {
+	// Record appends an entry to the audit trail.
+	Record(ctx context.Context, entry AuditEntry) error
+}
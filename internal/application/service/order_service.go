@@ -0,0 +1,241 @@
+// Package service contains the application services (use cases).
+// Services orchestrate domain aggregates and driven ports; they contain no
+// transport or persistence details.
+//
+// In Hexagonal Architecture (ports & adapters):
+//   - Driving adapters (HTTP handlers, CLI) call services
+//   - Services call driven ports (repositories, publishers, loggers)
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/domain/order"
+)
+
+const (
+	// DefaultListLimit is the page size used when none is requested.
+	DefaultListLimit = 20
+
+	// MaxListLimit is the largest page size a caller may request.
+	MaxListLimit = 100
+)
+
+// CreateOrderInput contains the data required to place an order.
+type CreateOrderInput struct {
+	// CustomerID is the customer placing the order
+	CustomerID string
+
+	// Currency is the ISO 4217 currency code
+	Currency string
+
+	// Lines are the ordered line items
+	Lines []order.LineItem
+}
+
+// OrderService implements the order use cases.
+type OrderService struct {
+	repo   port.OrderRepository
+	logger port.Logger
+	audit  port.AuditLogger
+}
+
+// NewOrderService creates a new OrderService.
+//
+// Parameters:
+//   - repo: The order repository
+//   - logger: The logger to use
+//   - audit: The audit trail of order changes (optional, may be nil)
+//
+// Returns:
+//   - *OrderService: The service
+func NewOrderService(repo port.OrderRepository, logger port.Logger, audit port.AuditLogger) *OrderService {
+	return &OrderService{
+		repo:   repo,
+		logger: logger,
+		audit:  audit,
+	}
+}
+
+// CreateOrder places a new pending order.
+//
+// Parameters:
+//   - ctx: The request context
+//   - in: The order data
+//
+// Returns:
+//   - *order.Order: The created order
+//   - error: A domain validation error, an audit error or a repository error
+func (s *OrderService) CreateOrder(ctx context.Context, in CreateOrderInput) (*order.Order, error) {
+	o, err := order.New(in.CustomerID, in.Currency, in.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := port.AuditEntry{
+		Action:   string(order.EventOrderCreated),
+		Resource: order.AggregateType + "/" + o.ID(),
+		After: map[string]any{
+			"status":      o.Status(),
+			"customer_id": o.CustomerID(),
+			"total":       o.Total(),
+			"currency":    o.Currency(),
+		},
+	}
+	if err := s.save(ctx, o, audit); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("Order created",
+		"order_id", o.ID(),
+		"customer_id", o.CustomerID(),
+		"total", o.Total(),
+		"currency", o.Currency(),
+	)
+
+	return o, nil
+}
+
+// GetOrder returns a single order.
+//
+// Parameters:
+//   - ctx: The request context
+//   - id: The order ID
+//
+// Returns:
+//   - *order.Order: The order
+//   - error: order.ErrNotFound if it does not exist
+func (s *OrderService) GetOrder(ctx context.Context, id string) (*order.Order, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, &order.ValidationError{Field: "id", Err: order.ErrInvalidOrderID}
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// ListOrders returns a page of orders matching the filter.
+// Limit is clamped to [1, MaxListLimit] and defaults to DefaultListLimit.
+//
+// Parameters:
+//   - ctx: The request context
+//   - filter: The list filter
+//
+// Returns:
+//   - []*order.Order: The page of orders
+//   - int: The total number of matching orders
+//   - error: Any repository error
+func (s *OrderService) ListOrders(ctx context.Context, filter port.OrderFilter) ([]*order.Order, int, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, 0, &order.ValidationError{Field: "status", Err: order.ErrInvalidStatus}
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultListLimit
+	}
+	if filter.Limit > MaxListLimit {
+		filter.Limit = MaxListLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.List(ctx, filter)
+}
+
+// CancelOrder cancels an order.
+//
+// Parameters:
+//   - ctx: The request context
+//   - id: The order ID
+//
+// Returns:
+//   - *order.Order: The cancelled order
+//   - error: order.ErrNotFound, a *order.TransitionError or order.ErrConcurrentModification
+func (s *OrderService) CancelOrder(ctx context.Context, id string) (*order.Order, error) {
+	return s.UpdateStatus(ctx, id, order.StatusCancelled)
+}
+
+// UpdateStatus moves an order to a new status following the order state machine.
+//
+// Parameters:
+//   - ctx: The request context
+//   - id: The order ID
+//   - status: The requested status
+//
+// Returns:
+//   - *order.Order: The updated order
+//   - error: order.ErrNotFound, a *order.ValidationError, a *order.TransitionError,
+//     order.ErrConcurrentModification if the order changed since it was loaded,
+//     or an audit error
+func (s *OrderService) UpdateStatus(ctx context.Context, id string, status order.Status) (*order.Order, error) {
+	o, err := s.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := o.Status()
+	if err := o.TransitionTo(status); err != nil {
+		return nil, err
+	}
+
+	// Actions are named like the domain events (e.g., order.refunded)
+	audit := port.AuditEntry{
+		Action:   order.AggregateType + "." + string(o.Status()),
+		Resource: order.AggregateType + "/" + o.ID(),
+		Before:   map[string]any{"status": previous},
+		After:    map[string]any{"status": o.Status()},
+	}
+	if err := s.save(ctx, o, audit); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("Order status changed",
+		"order_id", o.ID(),
+		"from", previous,
+		"to", o.Status(),
+	)
+
+	return o, nil
+}
+
+// save stores o once its change is in the audit trail, when there is one.
+//
+// The entry is written first, so no change is stored without it: when the
+// audit trail cannot be written, the operation fails and nothing changes.
+// When the save fails after that, an entry suffixed ".failed" records that the
+// change did not happen.
+//
+// Parameters:
+//   - ctx: The request context
+//   - o: The order to save
+//   - entry: The audit entry of the change
+//
+// Returns:
+//   - error: An audit error or a repository error
+func (s *OrderService) save(ctx context.Context, o *order.Order, entry port.AuditEntry) error {
+	if s.audit == nil {
+		return s.repo.Save(ctx, o)
+	}
+
+	if err := s.audit.Record(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+
+	if err := s.repo.Save(ctx, o); err != nil {
+		failed := port.AuditEntry{
+			Action:   entry.Action + ".failed",
+			Resource: entry.Resource,
+			Before:   entry.Before,
+			After:    map[string]any{"error": err.Error()},
+		}
+		if auditErr := s.audit.Record(ctx, failed); auditErr != nil {
+			s.logger.WithContext(ctx).Error("Failed to record audit entry",
+				"action", failed.Action,
+				"resource", failed.Resource,
+				"error", auditErr,
+			)
+		}
+		return err
+	}
+	return nil
+}
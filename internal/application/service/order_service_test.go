@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/domain/order"
+	"github.com/hapkiduki/order-go/internal/infrastructure/logging"
+	"github.com/hapkiduki/order-go/internal/infrastructure/persistence/memory"
+	"github.com/hapkiduki/order-go/pkg/logger/loggertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditRecorder is a port.AuditLogger keeping its entries in memory.
+type auditRecorder struct {
+	entries []port.AuditEntry
+	err     error
+}
+
+func (a *auditRecorder) Record(_ context.Context, entry port.AuditEntry) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.entries = append(a.entries, entry)
+	return nil
+}
+
+func (a *auditRecorder) actions() []string {
+	actions := make([]string, len(a.entries))
+	for i, e := range a.entries {
+		actions[i] = e.Action
+	}
+	return actions
+}
+
+// failingRepository fails every Save with err.
+type failingRepository struct {
+	port.OrderRepository
+	err error
+}
+
+func (r *failingRepository) Save(context.Context, *order.Order) error {
+	return r.err
+}
+
+var testInput = CreateOrderInput{
+	CustomerID: "customer-1",
+	Currency:   "USD",
+	Lines:      []order.LineItem{{ProductID: "p-1", Name: "Widget", Quantity: 2, UnitPrice: 500}},
+}
+
+func TestOrderService_AuditsChanges(t *testing.T) {
+	ctx := context.Background()
+	audit := &auditRecorder{}
+	svc := NewOrderService(memory.NewOrderRepository(memory.NewOutbox()), logging.New(loggertest.New().Logger()), audit)
+
+	o, err := svc.CreateOrder(ctx, testInput)
+	require.NoError(t, err)
+	_, err = svc.CancelOrder(ctx, o.ID())
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"order.created", "order.cancelled"}, audit.actions())
+	assert.Equal(t, "order/"+o.ID(), audit.entries[1].Resource)
+	assert.Equal(t, map[string]any{"status": order.StatusPending}, audit.entries[1].Before)
+	assert.Equal(t, map[string]any{"status": order.StatusCancelled}, audit.entries[1].After)
+}
+
+func TestOrderService_AuditFailureChangesNothing(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewOrderRepository(memory.NewOutbox())
+	audit := &auditRecorder{}
+	svc := NewOrderService(repo, logging.New(loggertest.New().Logger()), audit)
+
+	o, err := svc.CreateOrder(ctx, testInput)
+	require.NoError(t, err)
+
+	audit.err = errors.New("disk full")
+
+	_, err = svc.CreateOrder(ctx, testInput)
+	assert.ErrorIs(t, err, audit.err)
+	_, total, err := repo.List(ctx, port.OrderFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total, "the order is not stored")
+
+	_, err = svc.CancelOrder(ctx, o.ID())
+	assert.ErrorIs(t, err, audit.err)
+	stored, err := repo.FindByID(ctx, o.ID())
+	require.NoError(t, err)
+	assert.Equal(t, order.StatusPending, stored.Status(), "the status is not changed")
+}
+
+func TestOrderService_SaveFailureIsAudited(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewOrderRepository(memory.NewOutbox())
+	audit := &auditRecorder{}
+	rec := loggertest.New()
+	svc := NewOrderService(repo, logging.New(rec.Logger()), audit)
+
+	o, err := svc.CreateOrder(ctx, testInput)
+	require.NoError(t, err)
+
+	svc = NewOrderService(&failingRepository{OrderRepository: repo, err: order.ErrConcurrentModification}, logging.New(rec.Logger()), audit)
+	_, err = svc.CancelOrder(ctx, o.ID())
+	assert.ErrorIs(t, err, order.ErrConcurrentModification)
+
+	require.Equal(t, []string{"order.created", "order.cancelled", "order.cancelled.failed"}, audit.actions())
+	failed := audit.entries[2]
+	assert.Equal(t, "order/"+o.ID(), failed.Resource)
+	assert.Equal(t, map[string]any{"error": order.ErrConcurrentModification.Error()}, failed.After)
+	rec.AssertNoEntry(t, "info", "Order status changed")
+}
+
+func TestOrderService_WithoutAudit(t *testing.T) {
+	svc := NewOrderService(memory.NewOrderRepository(memory.NewOutbox()), logging.New(loggertest.New().Logger()), nil)
+
+	o, err := svc.CreateOrder(context.Background(), testInput)
+	require.NoError(t, err)
+	assert.Equal(t, order.StatusPending, o.Status())
+}
@@ -0,0 +1,84 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by the order aggregate.
+// Callers should use errors.Is to match them.
+var (
+	// ErrInvalidTransition is returned when a status change is not allowed by the state machine.
+	ErrInvalidTransition = errors.New("invalid order status transition")
+
+	// ErrInvalidStatus is returned when a status value is unknown.
+	ErrInvalidStatus = errors.New("invalid order status")
+
+	// ErrNotFound is returned by repositories when an order does not exist.
+	ErrNotFound = errors.New("order not found")
+
+	// ErrConcurrentModification is returned by repositories when the order was
+	// changed by someone else since it was loaded. Reload it and try again.
+	ErrConcurrentModification = errors.New("order was modified concurrently")
+
+	// ErrInvalidOrderID is returned when an order ID is empty or malformed.
+	ErrInvalidOrderID = errors.New("invalid order ID")
+
+	// ErrEmptyCustomerID is returned when an order is created without a customer.
+	ErrEmptyCustomerID = errors.New("customer ID is required")
+
+	// ErrNoLineItems is returned when an order is created without line items.
+	ErrNoLineItems = errors.New("order must contain at least one line item")
+
+	// ErrInvalidLineItem is returned when a line item has invalid data.
+	ErrInvalidLineItem = errors.New("invalid line item")
+
+	// ErrInvalidCurrency is returned when the currency is not a 3-letter ISO 4217 code.
+	ErrInvalidCurrency = errors.New("currency must be a 3-letter ISO 4217 code")
+
+	// ErrNotModifiable is returned when line items are changed after confirmation.
+	ErrNotModifiable = errors.New("order can no longer be modified")
+)
+
+// TransitionError describes a rejected status transition.
+// It wraps ErrInvalidTransition so it can be matched with errors.Is.
+type TransitionError struct {
+	// OrderID is the ID of the order the transition was attempted on
+	OrderID string
+
+	// From is the current status of the order
+	From Status
+
+	// To is the requested status
+	To Status
+}
+
+// Error implements the error interface.
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("order %s: cannot transition from %q to %q", e.OrderID, e.From, e.To)
+}
+
+// Unwrap returns ErrInvalidTransition.
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// ValidationError describes invalid input for a specific field.
+// It wraps one of the sentinel errors above.
+type ValidationError struct {
+	// Field is the name of the invalid field
+	Field string
+
+	// Err is the underlying sentinel error
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+// Unwrap returns the underlying sentinel error.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
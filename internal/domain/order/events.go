@@ -0,0 +1,107 @@
+package order
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AggregateType identifies orders in domain events.
+const AggregateType = "order"
+
+// EventType is the name of a domain event.
+// Event types are part of the integration contract with downstream services: never rename them.
+type EventType string
+
+const (
+	// EventOrderCreated is recorded when an order is placed.
+	EventOrderCreated EventType = "order.created"
+
+	// EventOrderConfirmed is recorded when an order is confirmed.
+	EventOrderConfirmed EventType = "order.confirmed"
+
+	// EventOrderPaid is recorded when an order is paid.
+	EventOrderPaid EventType = "order.paid"
+
+	// EventOrderShipped is recorded when an order is shipped.
+	EventOrderShipped EventType = "order.shipped"
+
+	// EventOrderDelivered is recorded when an order is delivered.
+	EventOrderDelivered EventType = "order.delivered"
+
+	// EventOrderCancelled is recorded when an order is cancelled.
+	EventOrderCancelled EventType = "order.cancelled"
+
+	// EventOrderRefunded is recorded when an order is refunded.
+	EventOrderRefunded EventType = "order.refunded"
+)
+
+// statusEvents maps each target status to the event recorded when an order reaches it.
+var statusEvents = map[Status]EventType{
+	StatusConfirmed: EventOrderConfirmed,
+	StatusPaid:      EventOrderPaid,
+	StatusShipped:   EventOrderShipped,
+	StatusDelivered: EventOrderDelivered,
+	StatusCancelled: EventOrderCancelled,
+	StatusRefunded:  EventOrderRefunded,
+}
+
+// Event is a domain event recorded by the Order aggregate.
+// Events are collected on the aggregate and persisted together with it,
+// so they are only published if the change they describe was stored.
+type Event struct {
+	// ID uniquely identifies the event (consumers use it for deduplication)
+	ID string
+
+	// Type is the event name
+	Type EventType
+
+	// OrderID is the aggregate the event belongs to
+	OrderID string
+
+	// CustomerID is the customer who placed the order
+	CustomerID string
+
+	// Status is the order status after the event
+	Status Status
+
+	// PreviousStatus is the order status before the event (empty for EventOrderCreated)
+	PreviousStatus Status
+
+	// Total is the order total in minor currency units
+	Total int64
+
+	// Currency is the ISO 4217 currency code
+	Currency string
+
+	// OccurredAt is when the event happened
+	OccurredAt time.Time
+}
+
+// Events returns a copy of the events recorded since the order was created or loaded.
+func (o *Order) Events() []Event {
+	out := make([]Event, len(o.events))
+	copy(out, o.events)
+	return out
+}
+
+// ClearEvents discards the recorded events.
+// Repositories call it once the events have been stored.
+func (o *Order) ClearEvents() {
+	o.events = nil
+}
+
+// record appends a domain event describing the order's current state.
+func (o *Order) record(eventType EventType, previous Status) {
+	o.events = append(o.events, Event{
+		ID:             uuid.New().String(),
+		Type:           eventType,
+		OrderID:        o.id,
+		CustomerID:     o.customerID,
+		Status:         o.status,
+		PreviousStatus: previous,
+		Total:          o.Total(),
+		Currency:       o.currency,
+		OccurredAt:     o.updatedAt,
+	})
+}
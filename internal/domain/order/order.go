@@ -0,0 +1,324 @@
+// Package order contains the Order aggregate and its business rules.
+// It is the core of the domain layer and has no dependencies on
+// infrastructure, transport or persistence concerns.
+//
+// Domain-Driven Design:
+//   - Order is the aggregate root; line items are only changed through it
+//   - Status changes go through an explicit state machine
+//   - Invariant violations are reported as typed domain errors
+package order
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LineItem is a single product line within an order.
+// Prices are expressed in minor currency units (e.g., cents) to avoid rounding errors.
+type LineItem struct {
+	// ProductID identifies the ordered product
+	ProductID string
+
+	// Name is the product name at the time of ordering
+	Name string
+
+	// Quantity is the number of units ordered
+	Quantity int
+
+	// UnitPrice is the price of a single unit in minor currency units
+	UnitPrice int64
+}
+
+// Subtotal returns the line total in minor currency units.
+func (li LineItem) Subtotal() int64 {
+	return li.UnitPrice * int64(li.Quantity)
+}
+
+// validate checks the line item invariants.
+func (li LineItem) validate() error {
+	switch {
+	case strings.TrimSpace(li.ProductID) == "":
+		return &ValidationError{Field: "product_id", Err: ErrInvalidLineItem}
+	case li.Quantity <= 0:
+		return &ValidationError{Field: "quantity", Err: ErrInvalidLineItem}
+	case li.UnitPrice < 0:
+		return &ValidationError{Field: "unit_price", Err: ErrInvalidLineItem}
+	}
+	return nil
+}
+
+// isCurrencyCode reports whether code has the shape of an ISO 4217 code: three
+// uppercase ASCII letters.
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range []byte(code) {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Order is the aggregate root for the ordering domain.
+// Fields are unexported so every change goes through methods that enforce invariants.
+type Order struct {
+	id         string
+	customerID string
+	currency   string
+	status     Status
+	lines      []LineItem
+	createdAt  time.Time
+	updatedAt  time.Time
+
+	// version is the stored version the order was loaded at (0 until first saved);
+	// repositories reject a save when the stored version has moved on
+	version int
+
+	// events are the domain events recorded but not yet persisted
+	events []Event
+}
+
+// New creates a pending order and records an EventOrderCreated event.
+//
+// Parameters:
+//   - customerID: The customer placing the order
+//   - currency: ISO 4217 currency code shared by all line items (e.g., "USD");
+//     surrounding spaces are trimmed and letters upper-cased
+//   - lines: The ordered line items (at least one)
+//
+// Returns:
+//   - *Order: The new order in StatusPending
+//   - error: A *ValidationError if any invariant is violated
+func New(customerID, currency string, lines []LineItem) (*Order, error) {
+	if strings.TrimSpace(customerID) == "" {
+		return nil, &ValidationError{Field: "customer_id", Err: ErrEmptyCustomerID}
+	}
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if !isCurrencyCode(currency) {
+		return nil, &ValidationError{Field: "currency", Err: ErrInvalidCurrency}
+	}
+	if len(lines) == 0 {
+		return nil, &ValidationError{Field: "lines", Err: ErrNoLineItems}
+	}
+	for _, line := range lines {
+		if err := line.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+	o := &Order{
+		id:         uuid.New().String(),
+		customerID: customerID,
+		currency:   currency,
+		status:     StatusPending,
+		lines:      make([]LineItem, len(lines)),
+		createdAt:  now,
+		updatedAt:  now,
+	}
+	copy(o.lines, lines)
+	o.record(EventOrderCreated, "")
+
+	return o, nil
+}
+
+// ID returns the order ID.
+func (o *Order) ID() string { return o.id }
+
+// CustomerID returns the ID of the customer who placed the order.
+func (o *Order) CustomerID() string { return o.customerID }
+
+// Currency returns the ISO 4217 currency code of the order.
+func (o *Order) Currency() string { return o.currency }
+
+// Status returns the current order status.
+func (o *Order) Status() Status { return o.status }
+
+// CreatedAt returns when the order was created.
+func (o *Order) CreatedAt() time.Time { return o.createdAt }
+
+// UpdatedAt returns when the order was last changed.
+func (o *Order) UpdatedAt() time.Time { return o.updatedAt }
+
+// Version returns the stored version of the order (0 if it was never saved).
+func (o *Order) Version() int { return o.version }
+
+// SetVersion records the version the order was stored at.
+// Repositories call it once the order has been saved.
+//
+// Parameters:
+//   - version: The new stored version
+func (o *Order) SetVersion(version int) {
+	o.version = version
+}
+
+// Lines returns a copy of the order line items.
+func (o *Order) Lines() []LineItem {
+	out := make([]LineItem, len(o.lines))
+	copy(out, o.lines)
+	return out
+}
+
+// Total returns the order total in minor currency units.
+func (o *Order) Total() int64 {
+	var total int64
+	for _, line := range o.lines {
+		total += line.Subtotal()
+	}
+	return total
+}
+
+// AddLine appends a line item. Only allowed while the order is pending.
+//
+// Parameters:
+//   - line: The line item to add
+//
+// Returns:
+//   - error: ErrNotModifiable or a *ValidationError
+func (o *Order) AddLine(line LineItem) error {
+	if o.status != StatusPending {
+		return ErrNotModifiable
+	}
+	if err := line.validate(); err != nil {
+		return err
+	}
+	o.lines = append(o.lines, line)
+	o.touch()
+	return nil
+}
+
+// RemoveLine removes the line item for the given product. Only allowed while the order is pending
+// and at least one line item remains.
+//
+// Parameters:
+//   - productID: The product whose line should be removed
+//
+// Returns:
+//   - error: ErrNotModifiable, ErrNoLineItems or a *ValidationError if the product is not in the order
+func (o *Order) RemoveLine(productID string) error {
+	if o.status != StatusPending {
+		return ErrNotModifiable
+	}
+	for i, line := range o.lines {
+		if line.ProductID != productID {
+			continue
+		}
+		if len(o.lines) == 1 {
+			return &ValidationError{Field: "lines", Err: ErrNoLineItems}
+		}
+		o.lines = append(o.lines[:i], o.lines[i+1:]...)
+		o.touch()
+		return nil
+	}
+	return &ValidationError{Field: "product_id", Err: ErrInvalidLineItem}
+}
+
+// Confirm moves the order from pending to confirmed.
+func (o *Order) Confirm() error { return o.TransitionTo(StatusConfirmed) }
+
+// MarkPaid moves the order from confirmed to paid.
+func (o *Order) MarkPaid() error { return o.TransitionTo(StatusPaid) }
+
+// Ship moves the order from paid to shipped.
+func (o *Order) Ship() error { return o.TransitionTo(StatusShipped) }
+
+// Deliver moves the order from shipped to delivered.
+func (o *Order) Deliver() error { return o.TransitionTo(StatusDelivered) }
+
+// Cancel cancels an order that has not been paid yet.
+func (o *Order) Cancel() error { return o.TransitionTo(StatusCancelled) }
+
+// Refund refunds an order that has been paid.
+func (o *Order) Refund() error { return o.TransitionTo(StatusRefunded) }
+
+// TransitionTo moves the order to the target status if the state machine allows it
+// and records the matching domain event (e.g., EventOrderPaid).
+//
+// Parameters:
+//   - target: The requested status
+//
+// Returns:
+//   - error: ErrInvalidStatus for unknown statuses, or a *TransitionError for disallowed transitions
+func (o *Order) TransitionTo(target Status) error {
+	if !target.IsValid() {
+		return &ValidationError{Field: "status", Err: ErrInvalidStatus}
+	}
+	if !o.status.CanTransitionTo(target) {
+		return &TransitionError{OrderID: o.id, From: o.status, To: target}
+	}
+	previous := o.status
+	o.status = target
+	o.touch()
+	o.record(statusEvents[target], previous)
+	return nil
+}
+
+// touch updates the modification timestamp.
+func (o *Order) touch() {
+	o.updatedAt = time.Now().UTC()
+}
+
+// Snapshot is a plain representation of an order's state.
+// It is used by persistence adapters to store and rebuild the aggregate.
+type Snapshot struct {
+	ID         string
+	CustomerID string
+	Currency   string
+	Status     Status
+	Lines      []LineItem
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+
+	// Version is the stored version the state was read at, used for optimistic locking
+	Version int
+}
+
+// Snapshot returns a copy of the order's current state.
+func (o *Order) Snapshot() Snapshot {
+	return Snapshot{
+		ID:         o.id,
+		CustomerID: o.customerID,
+		Currency:   o.currency,
+		Status:     o.status,
+		Lines:      o.Lines(),
+		CreatedAt:  o.createdAt,
+		UpdatedAt:  o.updatedAt,
+		Version:    o.version,
+	}
+}
+
+// Restore rebuilds an order from a snapshot without running creation rules.
+// Use it only in persistence adapters to load previously stored orders.
+//
+// Parameters:
+//   - s: The stored order state
+//
+// Returns:
+//   - *Order: The rebuilt order
+//   - error: A *ValidationError if the snapshot is corrupt
+func Restore(s Snapshot) (*Order, error) {
+	if s.ID == "" {
+		return nil, &ValidationError{Field: "id", Err: ErrInvalidOrderID}
+	}
+	if !s.Status.IsValid() {
+		return nil, &ValidationError{Field: "status", Err: ErrInvalidStatus}
+	}
+
+	o := &Order{
+		id:         s.ID,
+		customerID: s.CustomerID,
+		currency:   s.Currency,
+		status:     s.Status,
+		lines:      make([]LineItem, len(s.Lines)),
+		createdAt:  s.CreatedAt,
+		updatedAt:  s.UpdatedAt,
+		version:    s.Version,
+	}
+	copy(o.lines, s.Lines)
+
+	return o, nil
+}
@@ -0,0 +1,239 @@
+package order
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLines = []LineItem{
+	{ProductID: "sku-1", Name: "Keyboard", Quantity: 2, UnitPrice: 4999},
+	{ProductID: "sku-2", Name: "Mouse", Quantity: 1, UnitPrice: 1999},
+}
+
+// newTestOrder creates a valid order and moves it through the given statuses.
+func newTestOrder(t *testing.T, path ...Status) *Order {
+	t.Helper()
+
+	o, err := New("customer-1", "USD", testLines)
+	require.NoError(t, err)
+	for _, status := range path {
+		require.NoError(t, o.TransitionTo(status))
+	}
+	o.ClearEvents()
+	return o
+}
+
+func TestNew(t *testing.T) {
+	o, err := New("customer-1", "USD", testLines)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, o.ID())
+	assert.Equal(t, "customer-1", o.CustomerID())
+	assert.Equal(t, StatusPending, o.Status())
+	assert.Equal(t, testLines, o.Lines())
+	assert.Equal(t, int64(2*4999+1999), o.Total())
+	assert.Equal(t, o.CreatedAt(), o.UpdatedAt())
+	assert.Zero(t, o.Version())
+
+	events := o.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, EventOrderCreated, events[0].Type)
+	assert.Equal(t, o.ID(), events[0].OrderID)
+	assert.Equal(t, StatusPending, events[0].Status)
+	assert.Empty(t, events[0].PreviousStatus)
+	assert.Equal(t, o.Total(), events[0].Total)
+	assert.Equal(t, "USD", events[0].Currency)
+}
+
+func TestNew_CopiesLines(t *testing.T) {
+	lines := []LineItem{{ProductID: "sku-1", Quantity: 1, UnitPrice: 100}}
+	o, err := New("customer-1", "USD", lines)
+	require.NoError(t, err)
+
+	lines[0].Quantity = 99
+	o.Lines()[0].Quantity = 99
+	assert.Equal(t, 1, o.Lines()[0].Quantity)
+}
+
+func TestNew_Currency(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		want     string
+	}{
+		{"upper case", "USD", "USD"},
+		{"lower case", "eur", "EUR"},
+		{"mixed case", "gBp", "GBP"},
+		{"surrounding spaces", "  usd\t", "USD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := New("customer-1", tt.currency, testLines)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, o.Currency())
+			assert.Equal(t, tt.want, o.Events()[0].Currency)
+		})
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		customerID string
+		currency   string
+		lines      []LineItem
+		field      string
+		err        error
+	}{
+		{"empty customer", "", "USD", testLines, "customer_id", ErrEmptyCustomerID},
+		{"blank customer", "  ", "USD", testLines, "customer_id", ErrEmptyCustomerID},
+		{"empty currency", "customer-1", "", testLines, "currency", ErrInvalidCurrency},
+		{"blank currency", "customer-1", "   ", testLines, "currency", ErrInvalidCurrency},
+		{"two letters", "customer-1", "US", testLines, "currency", ErrInvalidCurrency},
+		{"four letters", "customer-1", "USDT", testLines, "currency", ErrInvalidCurrency},
+		{"digits", "customer-1", "840", testLines, "currency", ErrInvalidCurrency},
+		{"inner space", "customer-1", "U D", testLines, "currency", ErrInvalidCurrency},
+		{"non-ascii letters", "customer-1", "ÜSD", testLines, "currency", ErrInvalidCurrency},
+		{"no lines", "customer-1", "USD", nil, "lines", ErrNoLineItems},
+		{"no product", "customer-1", "USD", []LineItem{{Quantity: 1}}, "product_id", ErrInvalidLineItem},
+		{"zero quantity", "customer-1", "USD", []LineItem{{ProductID: "sku-1"}}, "quantity", ErrInvalidLineItem},
+		{"negative price", "customer-1", "USD", []LineItem{{ProductID: "sku-1", Quantity: 1, UnitPrice: -1}}, "unit_price", ErrInvalidLineItem},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := New(tt.customerID, tt.currency, tt.lines)
+
+			assert.Nil(t, o)
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Field)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestTransitionTo_Allowed(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   []Status
+		target Status
+		event  EventType
+	}{
+		{"confirm", nil, StatusConfirmed, EventOrderConfirmed},
+		{"cancel pending", nil, StatusCancelled, EventOrderCancelled},
+		{"pay", []Status{StatusConfirmed}, StatusPaid, EventOrderPaid},
+		{"cancel confirmed", []Status{StatusConfirmed}, StatusCancelled, EventOrderCancelled},
+		{"ship", []Status{StatusConfirmed, StatusPaid}, StatusShipped, EventOrderShipped},
+		{"deliver", []Status{StatusConfirmed, StatusPaid, StatusShipped}, StatusDelivered, EventOrderDelivered},
+		{"refund paid", []Status{StatusConfirmed, StatusPaid}, StatusRefunded, EventOrderRefunded},
+		{"refund shipped", []Status{StatusConfirmed, StatusPaid, StatusShipped}, StatusRefunded, EventOrderRefunded},
+		{"refund delivered", []Status{StatusConfirmed, StatusPaid, StatusShipped, StatusDelivered}, StatusRefunded, EventOrderRefunded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newTestOrder(t, tt.path...)
+			previous := o.Status()
+
+			require.NoError(t, o.TransitionTo(tt.target))
+
+			assert.Equal(t, tt.target, o.Status())
+			assert.False(t, o.UpdatedAt().Before(o.CreatedAt()))
+			events := o.Events()
+			require.Len(t, events, 1)
+			assert.Equal(t, tt.event, events[0].Type)
+			assert.Equal(t, tt.target, events[0].Status)
+			assert.Equal(t, previous, events[0].PreviousStatus)
+			assert.Equal(t, o.ID(), events[0].OrderID)
+		})
+	}
+}
+
+func TestTransitionTo_Forbidden(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   []Status
+		target Status
+	}{
+		{"pay pending", nil, StatusPaid},
+		{"ship pending", nil, StatusShipped},
+		{"refund pending", nil, StatusRefunded},
+		{"pending again", nil, StatusPending},
+		{"refund confirmed", []Status{StatusConfirmed}, StatusRefunded},
+		{"ship confirmed", []Status{StatusConfirmed}, StatusShipped},
+		{"cancel paid", []Status{StatusConfirmed, StatusPaid}, StatusCancelled},
+		{"deliver paid", []Status{StatusConfirmed, StatusPaid}, StatusDelivered},
+		{"cancel shipped", []Status{StatusConfirmed, StatusPaid, StatusShipped}, StatusCancelled},
+		{"cancel delivered", []Status{StatusConfirmed, StatusPaid, StatusShipped, StatusDelivered}, StatusCancelled},
+		{"confirm cancelled", []Status{StatusCancelled}, StatusConfirmed},
+		{"refund cancelled", []Status{StatusCancelled}, StatusRefunded},
+		{"refund refunded", []Status{StatusConfirmed, StatusPaid, StatusRefunded}, StatusRefunded},
+		{"ship refunded", []Status{StatusConfirmed, StatusPaid, StatusRefunded}, StatusShipped},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newTestOrder(t, tt.path...)
+			before := o.Snapshot()
+
+			err := o.TransitionTo(tt.target)
+
+			var transitionErr *TransitionError
+			require.ErrorAs(t, err, &transitionErr)
+			assert.True(t, errors.Is(err, ErrInvalidTransition))
+			assert.Equal(t, TransitionError{OrderID: o.ID(), From: before.Status, To: tt.target}, *transitionErr)
+			assert.Equal(t, before, o.Snapshot(), "unchanged")
+			assert.Empty(t, o.Events())
+		})
+	}
+}
+
+func TestTransitionTo_UnknownStatus(t *testing.T) {
+	o := newTestOrder(t)
+
+	err := o.TransitionTo("archived")
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "status", validationErr.Field)
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+	assert.Equal(t, StatusPending, o.Status())
+}
+
+func TestOrder_Refund(t *testing.T) {
+	o := newTestOrder(t)
+
+	// Unpaid orders are cancelled, not refunded
+	assert.ErrorIs(t, o.Refund(), ErrInvalidTransition)
+	require.NoError(t, o.Confirm())
+	assert.ErrorIs(t, o.Refund(), ErrInvalidTransition)
+
+	require.NoError(t, o.MarkPaid())
+	require.NoError(t, o.Ship())
+	require.NoError(t, o.Deliver())
+	require.NoError(t, o.Refund())
+	assert.True(t, o.Status().IsTerminal())
+
+	var types []EventType
+	for _, e := range o.Events() {
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []EventType{
+		EventOrderConfirmed, EventOrderPaid, EventOrderShipped, EventOrderDelivered, EventOrderRefunded,
+	}, types)
+	assert.ErrorIs(t, o.Refund(), ErrInvalidTransition, "refunded once")
+	assert.ErrorIs(t, o.Cancel(), ErrInvalidTransition)
+}
+
+func TestOrder_LinesOnlyChangeWhilePending(t *testing.T) {
+	o := newTestOrder(t)
+	require.NoError(t, o.AddLine(LineItem{ProductID: "sku-3", Quantity: 1, UnitPrice: 500}))
+	require.NoError(t, o.RemoveLine("sku-1"))
+	assert.Len(t, o.Lines(), 2)
+
+	require.NoError(t, o.Confirm())
+	assert.ErrorIs(t, o.AddLine(LineItem{ProductID: "sku-4", Quantity: 1}), ErrNotModifiable)
+	assert.ErrorIs(t, o.RemoveLine("sku-2"), ErrNotModifiable)
+}
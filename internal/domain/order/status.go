@@ -0,0 +1,106 @@
+package order
+
+import "fmt"
+
+// Status represents the lifecycle state of an order.
+type Status string
+
+const (
+	// StatusPending is the initial status of a newly created order.
+	StatusPending Status = "pending"
+
+	// StatusConfirmed means the order has been accepted and stock reserved.
+	StatusConfirmed Status = "confirmed"
+
+	// StatusPaid means payment for the order has been captured.
+	StatusPaid Status = "paid"
+
+	// StatusShipped means the order has left the warehouse.
+	StatusShipped Status = "shipped"
+
+	// StatusDelivered means the order reached the customer. Terminal unless refunded.
+	StatusDelivered Status = "delivered"
+
+	// StatusCancelled means the order was cancelled before payment. Terminal.
+	StatusCancelled Status = "cancelled"
+
+	// StatusRefunded means a paid order was refunded. Terminal.
+	StatusRefunded Status = "refunded"
+)
+
+// transitions is the order state machine.
+// Each status maps to the set of statuses it may move to.
+//
+//	pending ──► confirmed ──► paid ──► shipped ──► delivered
+//	   │            │           │         │            │
+//	   ▼            ▼           ▼         ▼            ▼
+//	cancelled   cancelled    refunded  refunded     refunded
+var transitions = map[Status][]Status{
+	StatusPending:   {StatusConfirmed, StatusCancelled},
+	StatusConfirmed: {StatusPaid, StatusCancelled},
+	StatusPaid:      {StatusShipped, StatusRefunded},
+	StatusShipped:   {StatusDelivered, StatusRefunded},
+	StatusDelivered: {StatusRefunded},
+	StatusCancelled: {},
+	StatusRefunded:  {},
+}
+
+// ParseStatus converts a string into a Status.
+//
+// Parameters:
+//   - s: The status string (e.g., "pending")
+//
+// Returns:
+//   - Status: The parsed status
+//   - error: ErrInvalidStatus if the value is unknown
+func ParseStatus(s string) (Status, error) {
+	status := Status(s)
+	if !status.IsValid() {
+		return "", fmt.Errorf("%w: %q", ErrInvalidStatus, s)
+	}
+	return status, nil
+}
+
+// String implements fmt.Stringer.
+func (s Status) String() string {
+	return string(s)
+}
+
+// IsValid reports whether s is a known status.
+func (s Status) IsValid() bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+// IsTerminal reports whether no further transitions are possible from s.
+func (s Status) IsTerminal() bool {
+	next, ok := transitions[s]
+	return ok && len(next) == 0
+}
+
+// CanTransitionTo reports whether the state machine allows moving from s to target.
+//
+// Parameters:
+//   - target: The requested status
+//
+// Returns:
+//   - bool: true if the transition is allowed
+func (s Status) CanTransitionTo(target Status) bool {
+	for _, next := range transitions[s] {
+		if next == target {
+			return true
+		}
+	}
+	return false
+}
+
+// NextStatuses returns the statuses reachable from s in one step.
+//
+// Returns:
+//   - []Status: A copy of the allowed target statuses
+func (s Status) NextStatuses() []Status {
+	next := transitions[s]
+	out := make([]Status, len(next))
+	copy(out, next)
+	return out
+}
@@ -0,0 +1,286 @@
+// Package audit provides a tamper-evident, file-backed implementation of
+// port.AuditLogger.
+//
+// Entries are appended to their own file as JSON lines, separate from the
+// application logs. Each line carries the hash of the previous one, so
+// editing, inserting or deleting an entry breaks the chain from that point
+// on (see Verify). With a hash key, the chain is an HMAC and cannot be
+// recomputed after an edit without the key.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/pkg/ctxkeys"
+)
+
+// anonymousActor is recorded when neither the entry nor the context names the actor.
+const anonymousActor = "anonymous"
+
+// TornSuffix is appended to the audit file path to name the file keeping the
+// incomplete last lines Open removed (see Open).
+const TornSuffix = ".torn"
+
+// Record is a line of the audit file.
+type Record struct {
+	// Seq numbers the entries from 1, without gaps
+	Seq uint64 `json:"seq"`
+
+	// Time is when the entry was recorded (UTC)
+	Time time.Time `json:"time"`
+
+	// The fields of port.AuditEntry; states are stored as JSON
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	Resource  string          `json:"resource"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	RequestID string          `json:"request_id,omitempty"`
+	ClientIP  string          `json:"client_ip,omitempty"`
+
+	// PrevHash is the Hash of the previous entry (empty for the first one)
+	PrevHash string `json:"prev_hash"`
+
+	// Hash covers every other field, PrevHash included
+	Hash string `json:"hash,omitempty"`
+}
+
+// digest computes the hash of r, ignoring its Hash field.
+func (r Record) digest(key []byte) (string, error) {
+	r.Hash = ""
+	body, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+
+	var h hash.Hash
+	if len(key) > 0 {
+		h = hmac.New(sha256.New, key)
+	} else {
+		h = sha256.New()
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// FileLogger appends hash-chained audit entries to a file.
+// It is safe for concurrent use.
+type FileLogger struct {
+	key []byte
+
+	mu       sync.Mutex
+	file     *os.File
+	seq      uint64
+	lastHash string
+
+	// torn is the size of the incomplete last line removed by Open
+	torn int64
+}
+
+// Compile-time check that FileLogger implements port.AuditLogger.
+var _ port.AuditLogger = (*FileLogger)(nil)
+
+// Open opens (or creates) the audit file and resumes its chain. A file whose
+// chain is broken is refused, so new entries are never chained to tampered ones.
+//
+// A last line without its newline is a write interrupted by a crash: Record
+// never returned for it, so its caller did not make the change. Open moves it
+// to path+TornSuffix and truncates it from the file instead of refusing to
+// start forever; Torn reports its size.
+//
+// Parameters:
+//   - path: The audit file
+//   - key: The HMAC key of the chain (empty for plain SHA-256)
+//
+// Returns:
+//   - *FileLogger: The audit logger; call Close on shutdown
+//   - error: If the file cannot be opened or its chain is broken
+func Open(path, key string) (*FileLogger, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create audit directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit file: %w", err)
+	}
+
+	torn, err := removeTornLine(file, path+TornSuffix)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("audit file %s: %w", path, err)
+	}
+
+	result, err := Verify(file, key)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("audit file %s: %w", path, err)
+	}
+
+	return &FileLogger{
+		key:      []byte(key),
+		file:     file,
+		seq:      result.Entries,
+		lastHash: result.LastHash,
+		torn:     torn,
+	}, nil
+}
+
+// Torn returns the size of the incomplete last line Open moved to the
+// TornSuffix file, or 0 when the file ended with a complete entry.
+//
+// Returns:
+//   - int64: The size of the removed line in bytes
+func (l *FileLogger) Torn() int64 {
+	return l.torn
+}
+
+// removeTornLine moves the last line of file to tornPath when it has no
+// newline, and truncates it from file.
+//
+// Parameters:
+//   - file: The audit file, opened for reading and writing
+//   - tornPath: The file the line is appended to
+//
+// Returns:
+//   - int64: The size of the removed line (0 when there is none)
+//   - error: If the file cannot be read, copied or truncated
+func removeTornLine(file *os.File, tornPath string) (int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("stat: %w", err)
+	}
+	size := info.Size()
+	if size == 0 {
+		return 0, nil
+	}
+
+	// Find the start of the last line, reading backwards
+	start := size
+	chunk := make([]byte, 64<<10)
+	for start > 0 {
+		n := min(int64(len(chunk)), start)
+		if _, err := file.ReadAt(chunk[:n], start-n); err != nil {
+			return 0, fmt.Errorf("read: %w", err)
+		}
+		if i := bytes.LastIndexByte(chunk[:n], '\n'); i >= 0 {
+			if start == size && i == int(n)-1 {
+				return 0, nil // ends with a newline
+			}
+			start -= n - int64(i) - 1
+			break
+		}
+		start -= n
+	}
+
+	line := make([]byte, size-start)
+	if _, err := file.ReadAt(line, start); err != nil {
+		return 0, fmt.Errorf("read: %w", err)
+	}
+	torn, err := os.OpenFile(tornPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("open torn file: %w", err)
+	}
+	defer torn.Close()
+	if _, err := torn.Write(append(line, '\n')); err != nil {
+		return 0, fmt.Errorf("write torn file: %w", err)
+	}
+	if err := torn.Sync(); err != nil {
+		return 0, fmt.Errorf("sync torn file: %w", err)
+	}
+
+	if err := file.Truncate(start); err != nil {
+		return 0, fmt.Errorf("truncate: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return 0, fmt.Errorf("sync: %w", err)
+	}
+	return size - start, nil
+}
+
+// Record implements port.AuditLogger. The entry is synced to disk before
+// Record returns.
+func (l *FileLogger) Record(ctx context.Context, entry port.AuditEntry) error {
+	record := Record{
+		Time:      time.Now().UTC(),
+		Actor:     entry.Actor,
+		Action:    entry.Action,
+		Resource:  entry.Resource,
+		RequestID: entry.RequestID,
+		ClientIP:  entry.ClientIP,
+	}
+	if record.Actor == "" {
+		record.Actor = ctxkeys.Get(ctx, ctxkeys.UserID)
+	}
+	if record.Actor == "" {
+		record.Actor = anonymousActor
+	}
+	if record.RequestID == "" {
+		record.RequestID = ctxkeys.Get(ctx, ctxkeys.RequestID)
+	}
+	if record.ClientIP == "" {
+		record.ClientIP = ctxkeys.Get(ctx, ctxkeys.ClientIP)
+	}
+
+	var err error
+	if record.Before, err = marshalState(entry.Before); err != nil {
+		return fmt.Errorf("audit %s before: %w", entry.Action, err)
+	}
+	if record.After, err = marshalState(entry.After); err != nil {
+		return fmt.Errorf("audit %s after: %w", entry.Action, err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	record.Seq = l.seq + 1
+	record.PrevHash = l.lastHash
+	if record.Hash, err = record.digest(l.key); err != nil {
+		return fmt.Errorf("audit %s: %w", entry.Action, err)
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("audit %s: %w", entry.Action, err)
+	}
+
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write audit entry: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("sync audit file: %w", err)
+	}
+
+	l.seq = record.Seq
+	l.lastHash = record.Hash
+	return nil
+}
+
+// Close closes the audit file.
+//
+// Returns:
+//   - error: Any error closing the file
+func (l *FileLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// marshalState encodes a before/after state; nil stays empty.
+func marshalState(state any) (json.RawMessage, error) {
+	if state == nil {
+		return nil, nil
+	}
+	return json.Marshal(state)
+}
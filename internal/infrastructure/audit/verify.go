@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// maxLineSize limits the size of an audit line (states included).
+const maxLineSize = 16 << 20
+
+// VerifyResult summarizes an intact audit chain.
+type VerifyResult struct {
+	// Entries is the number of entries (the Seq of the last one)
+	Entries uint64
+
+	// LastHash is the hash of the last entry. Keep a copy elsewhere (e.g., in
+	// a ticket or another system) to also detect entries deleted from the end.
+	LastHash string
+}
+
+// ChainError reports where an audit chain is broken.
+type ChainError struct {
+	// Line is the line number in the file, from 1
+	Line int
+
+	// Seq is the sequence number expected on that line
+	Seq uint64
+
+	// Reason describes the problem
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("chain broken at line %d (seq %d): %s", e.Line, e.Seq, e.Reason)
+}
+
+// Verify checks an audit chain: sequence numbers follow each other, each
+// entry points to the previous one and its hash matches its content. A last
+// line without its newline (a write interrupted by a crash, which Open
+// removes) is reported as a broken chain.
+//
+// Parameters:
+//   - r: The audit file content
+//   - key: The HMAC key the chain was written with (empty for plain SHA-256)
+//
+// Returns:
+//   - VerifyResult: The number of entries and the last hash
+//   - error: A *ChainError when the chain is broken, or a read error
+func Verify(r io.Reader, key string) (VerifyResult, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxLineSize)
+
+	// Lines are complete once their newline is written
+	torn := false
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if atEOF && token != nil && data[advance-1] != '\n' {
+			torn = true
+		}
+		return advance, token, err
+	})
+
+	var result VerifyResult
+	for line := 1; scanner.Scan(); line++ {
+		broken := func(format string, args ...any) error {
+			return &ChainError{Line: line, Seq: result.Entries + 1, Reason: fmt.Sprintf(format, args...)}
+		}
+
+		if torn {
+			return result, broken("incomplete last line (write interrupted by a crash)")
+		}
+
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return result, broken("invalid entry: %v", err)
+		}
+		if record.Seq != result.Entries+1 {
+			return result, broken("found seq %d (entries missing or reordered)", record.Seq)
+		}
+		if record.PrevHash != result.LastHash {
+			return result, broken("previous hash does not match (an entry before was changed or removed)")
+		}
+		digest, err := record.digest([]byte(key))
+		if err != nil {
+			return result, broken("invalid entry: %v", err)
+		}
+		if record.Hash != digest {
+			return result, broken("hash does not match (entry changed, or wrong hash key)")
+		}
+
+		result.Entries = record.Seq
+		result.LastHash = record.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("read audit file: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,416 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/hapkiduki/order-go/pkg/logger"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// unsetEnv hides the environment variables that select or override the
+// settings under test; viper ignores empty variables.
+func unsetEnv(t *testing.T) {
+	t.Helper()
+
+	for _, env := range []string{"OPS_ENVIRONMENT", "OPS_APP_ENVIRONMENT", "PORT", "OPS_SERVER_PORT", "OPS_LOG_LEVEL"} {
+		t.Setenv(env, "")
+	}
+}
+
+// memFs returns a filesystem holding files (path -> content).
+func memFs(t *testing.T, files map[string]string) afero.Fs {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	for path, content := range files {
+		require.NoError(t, afero.WriteFile(fs, path, []byte(content), 0o644))
+	}
+	return fs
+}
+
+func TestLoadFS(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		env   map[string]string
+		check func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "no files uses the defaults",
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "development", cfg.App.Environment)
+				assert.Equal(t, 8080, cfg.Server.Port)
+				assert.Equal(t, "info", cfg.Log.Level)
+			},
+		},
+		{
+			name: "base, environment and local layers in order",
+			files: map[string]string{
+				"configs/config.yaml": `
+app: {name: base, environment: staging}
+server: {port: 8000}
+log: {level: debug, format: console}
+`,
+				"configs/config.staging.yaml": `
+server: {port: 8100}
+log: {level: warn}
+`,
+				"configs/config.local.yaml": `
+server: {port: 8200}
+`,
+			},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "base", cfg.App.Name)
+				assert.Equal(t, "console", cfg.Log.Format, "from the base layer")
+				assert.Equal(t, "warn", cfg.Log.Level, "from the environment layer")
+				assert.Equal(t, 8200, cfg.Server.Port, "from the local layer")
+			},
+		},
+		{
+			name: "other environment layers are ignored",
+			files: map[string]string{
+				"configs/config.yaml":            `app: {environment: staging}`,
+				"configs/config.production.yaml": `server: {port: 9999}`,
+			},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 8080, cfg.Server.Port)
+			},
+		},
+		{
+			name: "missing environment layer",
+			files: map[string]string{
+				"configs/config.yaml":       `app: {environment: staging}`,
+				"configs/config.local.yaml": `server: {port: 8200}`,
+			},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "staging", cfg.App.Environment)
+				assert.Equal(t, 8200, cfg.Server.Port)
+			},
+		},
+		{
+			name: "missing base layer",
+			files: map[string]string{
+				"configs/config.development.yml": `log: {level: debug}`,
+			},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "debug", cfg.Log.Level)
+			},
+		},
+		{
+			name: "the environment variable selects the environment layer",
+			files: map[string]string{
+				"configs/config.yaml":         `app: {environment: development}`,
+				"configs/config.staging.yaml": `server: {port: 8100}`,
+			},
+			env: map[string]string{"OPS_ENVIRONMENT": "staging"},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "staging", cfg.App.Environment)
+				assert.Equal(t, 8100, cfg.Server.Port)
+			},
+		},
+		{
+			name: "the first directory holding a layer wins",
+			files: map[string]string{
+				"config.yaml":         `server: {port: 8000}`,
+				"configs/config.yaml": `server: {port: 8100}`,
+			},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 8000, cfg.Server.Port)
+			},
+		},
+		{
+			name: "redact_keys merge with the defaults",
+			files: map[string]string{
+				"configs/config.yaml": `
+log:
+  redact_keys: {ssn: mask, cookie: "off"}
+`,
+			},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "mask", cfg.Log.RedactKeys["ssn"], "added")
+				assert.Equal(t, "off", cfg.Log.RedactKeys["cookie"], "overridden")
+				assert.Equal(t, "drop", cfg.Log.RedactKeys["password"], "kept from the defaults")
+			},
+		},
+		{
+			name: "feature flags merge across layers",
+			files: map[string]string{
+				"configs/config.yaml": `
+app: {environment: staging}
+feature_flags:
+  flags:
+    new_checkout: {enabled: true, tenants: [acme], percentage: 10}
+    legacy_export: {enabled: true}
+`,
+				"configs/config.staging.yaml": `
+feature_flags:
+  flags:
+    new_checkout: {percentage: 50}
+`,
+				"configs/config.local.yaml": `
+feature_flags:
+  flags:
+    dark_mode: {enabled: true}
+`,
+			},
+			check: func(t *testing.T, cfg *Config) {
+				require.Len(t, cfg.FeatureFlags.Flags, 3)
+
+				flag := cfg.FeatureFlags.Flags["new_checkout"]
+				assert.True(t, flag.Enabled, "kept from the base layer")
+				assert.Equal(t, []string{"acme"}, flag.Tenants, "kept from the base layer")
+				require.NotNil(t, flag.Percentage)
+				assert.Equal(t, 50.0, *flag.Percentage, "from the environment layer")
+
+				assert.True(t, cfg.FeatureFlags.Flags["legacy_export"].Enabled)
+				assert.True(t, cfg.FeatureFlags.Flags["dark_mode"].Enabled)
+			},
+		},
+		{
+			name: "lists are replaced, not merged",
+			files: map[string]string{
+				"configs/config.yaml":       `server: {cors_allowed_origins: [https://a.example, https://b.example]}`,
+				"configs/config.local.yaml": `server: {cors_allowed_origins: [https://c.example]}`,
+			},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, []string{"https://c.example"}, cfg.Server.CORSAllowedOrigins)
+			},
+		},
+		{
+			name: "environment variables win over every layer",
+			files: map[string]string{
+				"configs/config.yaml":         `app: {environment: staging}`,
+				"configs/config.staging.yaml": `log: {level: warn}`,
+				"configs/config.local.yaml":   `server: {port: 8200}`,
+			},
+			env: map[string]string{"OPS_SERVER_PORT": "9000", "OPS_LOG_LEVEL": "error"},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 9000, cfg.Server.Port)
+				assert.Equal(t, "error", cfg.Log.Level)
+			},
+		},
+		{
+			name: "OPS_SERVER_PORT wins over PORT",
+			env:  map[string]string{"OPS_SERVER_PORT": "9000", "PORT": "9100"},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 9000, cfg.Server.Port)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unsetEnv(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := LoadFS(memFs(t, tt.files))
+			require.NoError(t, err)
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoadFS_Errors(t *testing.T) {
+	unsetEnv(t)
+
+	t.Run("invalid YAML", func(t *testing.T) {
+		_, err := LoadFS(memFs(t, map[string]string{
+			"configs/config.local.yaml": "server: [port",
+		}))
+		assert.ErrorContains(t, err, "configs/config.local.yaml")
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		_, err := LoadFS(memFs(t, map[string]string{
+			"configs/config.yaml": `
+server: {port: 0, request_timeout: 2m}
+log:
+  level: verbose
+  sinks:
+    - {output: logs/app.log}
+    - {output: ./logs/app.log, level: error}
+`,
+		}))
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		keys := make([]string, len(validationErr.Errors))
+		for i, e := range validationErr.Errors {
+			keys[i] = e.Key
+		}
+		assert.Contains(t, keys, "server.port")
+		assert.Contains(t, keys, "log.level")
+		assert.Contains(t, keys, "idempotency.lock_ttl", "must cover server.request_timeout")
+		assert.Contains(t, keys, "log.sinks[1].output", "same file as log.sinks[0]")
+	})
+}
+
+func TestDefaults_RedactionMatchesLogger(t *testing.T) {
+	unsetEnv(t)
+
+	cfg, err := LoadFS(afero.NewMemMapFs())
+	require.NoError(t, err)
+
+	defaults := logger.DefaultRedactionConfig()
+	assert.Equal(t, defaults.Keys, cfg.Log.RedactKeys)
+	assert.Equal(t, defaults.Patterns, cfg.Log.RedactPatterns)
+}
+
+func TestSecret_Redacted(t *testing.T) {
+	secret := Secret("postgres://order:s3cret@db/orders")
+
+	var zapOut, slogOut bytes.Buffer
+	zapLogger := zap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.AddSync(&zapOut), zapcore.DebugLevel,
+	))
+	slogLogger := slog.New(slog.NewJSONHandler(&slogOut, nil))
+
+	tests := []struct {
+		name   string
+		output func() string
+		want   string
+	}{
+		{"%v", func() string { return fmt.Sprintf("%v", secret) }, "[REDACTED]"},
+		{"%+v", func() string { return fmt.Sprintf("%+v", secret) }, "[REDACTED]"},
+		{"%s", func() string { return fmt.Sprintf("%s", secret) }, "[REDACTED]"},
+		{"%q", func() string { return fmt.Sprintf("%q", secret) }, "[REDACTED]"},
+		{"%x", func() string { return fmt.Sprintf("%x", secret) }, "[REDACTED]"},
+		{"%#v", func() string { return fmt.Sprintf("%#v", secret) }, `config.Secret("[REDACTED]")`},
+		{"in a struct", func() string {
+			return fmt.Sprintf("%+v", DatabaseConfig{DSN: secret})
+		}, "DSN:[REDACTED]"},
+		{"json.Marshal", func() string {
+			out, err := json.Marshal(DatabaseConfig{DSN: secret})
+			require.NoError(t, err)
+			return string(out)
+		}, `"DSN":"[REDACTED]"`},
+		{"zap field", func() string {
+			zapLogger.Info("config", zap.Any("dsn", secret))
+			return zapOut.String()
+		}, `"dsn":"[REDACTED]"`},
+		{"zap sugar", func() string {
+			zapLogger.Sugar().Infow("config", "dsn", secret)
+			return zapOut.String()
+		}, `"dsn":"[REDACTED]"`},
+		{"slog", func() string {
+			slogLogger.Info("config", "dsn", secret)
+			return slogOut.String()
+		}, `"dsn":"[REDACTED]"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zapOut.Reset()
+			slogOut.Reset()
+
+			out := tt.output()
+			assert.Contains(t, out, tt.want)
+			assert.NotContains(t, out, "s3cret")
+		})
+	}
+
+	assert.Equal(t, "postgres://order:s3cret@db/orders", secret.Value())
+	assert.Empty(t, fmt.Sprint(Secret("")), "an unset secret stays visibly empty")
+}
+
+func TestSecretFields(t *testing.T) {
+	var cfg Config
+	fields := secretFields(reflect.ValueOf(&cfg).Elem(), "")
+
+	assert.ElementsMatch(t, []string{
+		"log.redact_hash_key",
+		"database.dsn",
+		"redis.password",
+		"rabbitmq.url",
+		"admin.token",
+		"audit.hash_key",
+	}, slices.Collect(maps.Keys(fields)))
+
+	*fields["database.dsn"] = "dsn"
+	assert.Equal(t, "dsn", cfg.Database.DSN.Value(), "fields point into the config")
+}
+
+func TestLoadFS_SecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeSecret := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	tests := []struct {
+		name  string
+		env   map[string]string
+		check func(t *testing.T, cfg *Config)
+		err   string
+	}{
+		{
+			name: "read from the file, trailing newline trimmed",
+			env:  map[string]string{"OPS_DATABASE_DSN_FILE": writeSecret("dsn", "postgres://order:s3cret@db/orders\n")},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "postgres://order:s3cret@db/orders", cfg.Database.DSN.Value())
+			},
+		},
+		{
+			name: "CRLF trimmed, inner spaces kept",
+			env:  map[string]string{"OPS_ADMIN_TOKEN_FILE": writeSecret("token", "admin token 0123456789\r\n")},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "admin token 0123456789", cfg.Admin.Token.Value())
+			},
+		},
+		{
+			name: "an empty _FILE variable is ignored",
+			env:  map[string]string{"OPS_REDIS_PASSWORD_FILE": "", "OPS_REDIS_PASSWORD": "from-env"},
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "from-env", cfg.Redis.Password.Value())
+			},
+		},
+		{
+			name: "variable and file both set",
+			env: map[string]string{
+				"OPS_DATABASE_DSN":      "postgres://env",
+				"OPS_DATABASE_DSN_FILE": writeSecret("dsn-both", "postgres://file"),
+			},
+			err: "OPS_DATABASE_DSN and OPS_DATABASE_DSN_FILE are both set",
+		},
+		{
+			name: "missing file",
+			env:  map[string]string{"OPS_AUDIT_HASH_KEY_FILE": filepath.Join(dir, "missing")},
+			err:  "failed to read audit.hash_key from OPS_AUDIT_HASH_KEY_FILE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unsetEnv(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := LoadFS(afero.NewMemMapFs())
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				if err != nil {
+					assert.NotContains(t, err.Error(), "postgres://", "the secret itself is never echoed")
+				}
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, cfg)
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+)
+
+// Setting sources reported by Explain.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+)
+
+// exampleHeader is the comment block at the top of the example config file.
+const exampleHeader = `# ========================================================================
+# Example Configuration File
+# ========================================================================
+# This is an example configuration file. Copy to config.yaml for local use.
+# DO NOT commit config.yaml to version control if it contains secrets.
+#
+# Files are merged in layers, each one overriding only the keys it sets:
+#   1. config.yaml                (base, shared by all environments)
+#   2. config.<environment>.yaml  (e.g., config.production.yaml)
+#   3. config.local.yaml          (uncommitted local overrides)
+# Each layer is looked up in ./, ./configs and /etc/order-go (first match wins).
+#
+# Note: Environment variables take precedence over config file values.
+# Prefix environment variables with OPS_ (e.g., OPS_SERVER_PORT)
+# Secrets (database.dsn, redis.password, rabbitmq.url) can also be read from a
+# file named by the matching *_FILE variable (e.g., OPS_DATABASE_DSN_FILE),
+# as with Docker and Kubernetes secrets. Secrets are redacted in logs.
+#
+# The configuration is validated at startup and every problem is reported at once.
+# Generated from the Config struct tags: run "make config-example" after changing them.
+# ========================================================================
+`
+
+// Setting is one effective configuration value and where it came from.
+type Setting struct {
+	// Key is the configuration key (e.g., "server.port")
+	Key string
+
+	// Value is the effective value; secrets are Secret values and print redacted
+	Value any
+
+	// Source is where the value came from: SourceDefault, SourceFile or SourceEnv
+	Source string
+
+	// Origin details the source: the config file path or the env var name
+	Origin string
+}
+
+// field is a configuration leaf found by walking the Config struct.
+type field struct {
+	key   string
+	value reflect.Value
+	tag   reflect.StructField
+}
+
+// section is a top-level configuration section and its leaves.
+type section struct {
+	name   string
+	desc   string
+	fields []field
+}
+
+// Explain loads the configuration exactly like Load, without validating it,
+// and reports where each setting came from.
+//
+// Returns:
+//   - []Setting: Every setting, in Config order
+//   - *Config: The effective configuration (call Validate to check it)
+//   - []string: The config files merged, lowest precedence first
+//   - error: If the configuration cannot be loaded
+func Explain() ([]Setting, *Config, []string, error) {
+	fs := afero.NewOsFs()
+	v, files, err := newViper(fs)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	if err := loadSensitiveConfig(&cfg); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Read each layer on its own to find the last one setting a key
+	layers := make([]*viper.Viper, len(files))
+	for i, file := range files {
+		layers[i] = viper.New()
+		layers[i].SetConfigType("yaml")
+		if err := mergeConfigFile(fs, layers[i], file); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	var settings []Setting
+	for _, s := range sections(&cfg) {
+		for _, f := range s.fields {
+			source, origin := sourceOf(f, files, layers)
+			settings = append(settings, Setting{
+				Key:    f.key,
+				Value:  f.value.Interface(),
+				Source: source,
+				Origin: origin,
+			})
+		}
+	}
+	return settings, &cfg, files, nil
+}
+
+// sourceOf reports where the value of f came from, following the precedence of Load.
+func sourceOf(f field, files []string, layers []*viper.Viper) (string, string) {
+	env := envVar(f.key)
+	if f.tag.Type == secretType && os.Getenv(env+"_FILE") != "" {
+		return SourceEnv, env + "_FILE"
+	}
+
+	// Viper ignores empty env vars
+	if os.Getenv(env) != "" {
+		return SourceEnv, env
+	}
+	if bound, ok := boundEnvVars[f.key]; ok && os.Getenv(bound) != "" {
+		return SourceEnv, bound
+	}
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		if layers[i].InConfig(f.key) {
+			return SourceFile, files[i]
+		}
+	}
+	return SourceDefault, ""
+}
+
+// WriteEffective writes settings as YAML, annotating each key with its source.
+//
+// Parameters:
+//   - w: The destination
+//   - settings: The settings returned by Explain
+//
+// Returns:
+//   - error: Any write error
+func WriteEffective(w io.Writer, settings []Setting) error {
+	var b bytes.Buffer
+	current := ""
+	for _, s := range settings {
+		name, key, _ := strings.Cut(s.Key, ".")
+		if name != current {
+			if current != "" {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "%s:\n", name)
+			current = name
+		}
+
+		source := s.Source
+		if s.Origin != "" {
+			source += " " + s.Origin
+		}
+		fmt.Fprintf(&b, "  %s: %s  # %s\n", key, formatValue(reflect.ValueOf(s.Value)), source)
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// WriteExample writes a fully commented example config file built from the
+// defaults in setDefaults and the desc tags of the Config struct.
+// configs/config.example.yaml is generated with it.
+//
+// Parameters:
+//   - w: The destination
+//
+// Returns:
+//   - error: Any write error
+func WriteExample(w io.Writer) error {
+	v := viper.New()
+	setDefaults(v)
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return fmt.Errorf("failed to unmarshal defaults: %w", err)
+	}
+
+	var b bytes.Buffer
+	b.WriteString(exampleHeader)
+	for _, s := range sections(&cfg) {
+		b.WriteString("\n")
+		for _, line := range strings.Split(s.desc, "\n") {
+			fmt.Fprintf(&b, "# %s\n", line)
+		}
+		fmt.Fprintf(&b, "%s:\n", s.name)
+
+		for _, f := range s.fields {
+			name := f.key[len(s.name)+1:]
+
+			// Secrets are never written to the example, even if a default exists
+			value := formatValue(f.value)
+			if f.tag.Type == secretType {
+				value = `""`
+			}
+
+			line := fmt.Sprintf("  %s: %s", name, value)
+			if desc := f.tag.Tag.Get("desc"); desc != "" {
+				line += "  # " + desc
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// LoadFile loads the defaults and the given config files, ignoring
+// environment variables and *_FILE secrets, and validates the result. Use it
+// to check files offline before deploying them.
+//
+// Files are merged like the layers read by Load, in the given order
+// (e.g., config.yaml then config.production.yaml). Unknown keys are errors.
+//
+// Parameters:
+//   - paths: The config files (YAML), lowest precedence first
+//
+// Returns:
+//   - *Config: The configuration
+//   - error: If a file cannot be read, or a *ValidationError
+func LoadFile(paths ...string) (*Config, error) {
+	fs := afero.NewOsFs()
+	v := viper.New()
+	setDefaults(v)
+	v.SetConfigType("yaml")
+
+	for _, path := range paths {
+		if err := mergeConfigFile(fs, v, path); err != nil {
+			return nil, err
+		}
+	}
+
+	var cfg Config
+	if err := v.UnmarshalExact(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// sections walks cfg and returns its sections and leaves in declaration order.
+func sections(cfg *Config) []section {
+	root := reflect.ValueOf(cfg).Elem()
+	t := root.Type()
+
+	var result []section
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		s := section{
+			name: sf.Tag.Get("mapstructure"),
+			desc: sf.Tag.Get("desc"),
+		}
+
+		sv := root.Field(i)
+		st := sf.Type
+		for j := range st.NumField() {
+			leaf := st.Field(j)
+			s.fields = append(s.fields, field{
+				key:   s.name + "." + leaf.Tag.Get("mapstructure"),
+				value: sv.Field(j),
+				tag:   leaf,
+			})
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// formatValue renders a configuration value as a YAML scalar or flow sequence.
+func formatValue(v reflect.Value) string {
+	switch value := v.Interface().(type) {
+	case Secret:
+		return yamlScalar(value.String())
+	case time.Duration:
+		return formatDuration(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case []string:
+		items := make([]string, len(value))
+		for i, item := range value {
+			items[i] = yamlScalar(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case string:
+		return yamlScalar(value)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return "null"
+		}
+		return formatValue(v.Elem())
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range v.Len() {
+			items[i] = formatValue(v.Index(i))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Map:
+		// Sorted keys keep the output deterministic
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+		})
+		items := make([]string, len(keys))
+		for i, key := range keys {
+			items[i] = yamlScalar(fmt.Sprint(key.Interface())) + ": " + formatValue(v.MapIndex(key))
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	case reflect.Struct:
+		// Zero values are left out to keep flow mappings short
+		var items []string
+		for i := range v.NumField() {
+			if v.Field(i).IsZero() {
+				continue
+			}
+			items = append(items, v.Type().Field(i).Tag.Get("mapstructure")+": "+formatValue(v.Field(i)))
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// yamlScalar quotes s when YAML requires it or when it contains spaces,
+// always with double quotes.
+func yamlScalar(s string) string {
+	out, err := yaml.Marshal(s)
+	if err != nil || strings.Contains(s, " ") {
+		return strconv.Quote(s)
+	}
+
+	plain := strings.TrimSuffix(string(out), "\n")
+	if plain != s {
+		return strconv.Quote(s)
+	}
+	return plain
+}
+
+// formatDuration renders d the way it is usually written in config files
+// (1h instead of 1h0m0s).
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
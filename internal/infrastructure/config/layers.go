@@ -0,0 +1,153 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+)
+
+// configPaths are the directories searched for each config file layer, in order.
+// The first directory containing a layer wins.
+var configPaths = []string{".", "./configs", "/etc/order-go"}
+
+// configExtensions are the accepted config file extensions, in order.
+var configExtensions = []string{"yaml", "yml"}
+
+// Config file layer names (without extension).
+const (
+	// baseLayer holds the settings shared by all environments
+	baseLayer = "config"
+
+	// localLayer holds uncommitted developer overrides
+	localLayer = "config.local"
+)
+
+// configLayers returns the config file layer names, lowest precedence first.
+//
+// Parameters:
+//   - environment: The application environment (e.g., "production")
+//
+// Returns:
+//   - []string: config, config.<environment>, config.local
+func configLayers(environment string) []string {
+	layers := []string{baseLayer}
+	if environment != "" {
+		layers = append(layers, baseLayer+"."+environment)
+	}
+	return append(layers, localLayer)
+}
+
+// findConfigFiles returns the existing config file of every layer, lowest
+// precedence first. Missing layers are skipped.
+//
+// Parameters:
+//   - fs: The filesystem to search
+//   - environment: The application environment
+//
+// Returns:
+//   - []string: The config files to merge, in order
+//   - error: If the filesystem cannot be read
+func findConfigFiles(fs afero.Fs, environment string) ([]string, error) {
+	var files []string
+	for _, layer := range configLayers(environment) {
+		file, err := findConfigFile(fs, layer)
+		if err != nil {
+			return nil, err
+		}
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// findConfigFile returns the first existing file for layer in configPaths, or "".
+func findConfigFile(fs afero.Fs, layer string) (string, error) {
+	for _, dir := range configPaths {
+		for _, ext := range configExtensions {
+			path := filepath.Join(dir, layer+"."+ext)
+			info, err := fs.Stat(path)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return "", fmt.Errorf("failed to stat config file %s: %w", path, err)
+			}
+			if !info.IsDir() {
+				return path, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// mergeConfigLayers merges the config file layers into v.
+//
+// The base file is read first so that it can set app.environment; env vars
+// still take precedence when selecting the environment layer. Maps are merged
+// key by key at every depth, while scalars and lists of a later layer replace
+// the earlier value.
+//
+// Parameters:
+//   - fs: The filesystem the layers are read from
+//   - v: The viper instance to merge into
+//
+// Returns:
+//   - []string: The config files merged, lowest precedence first
+//   - error: If a file cannot be read or parsed
+func mergeConfigLayers(fs afero.Fs, v *viper.Viper) ([]string, error) {
+	v.SetConfigType("yaml")
+
+	base, err := findConfigFile(fs, baseLayer)
+	if err != nil {
+		return nil, err
+	}
+	if base != "" {
+		if err := mergeConfigFile(fs, v, base); err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := findConfigFiles(fs, v.GetString("app.environment"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if file == base {
+			continue
+		}
+		if err := mergeConfigFile(fs, v, file); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// mergeConfigFile merges a single YAML file into v.
+func mergeConfigFile(fs afero.Fs, v *viper.Viper, path string) error {
+	content, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	if err := v.MergeConfig(bytes.NewReader(content)); err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// isConfigFileName reports whether name (a base name) may be a config file
+// layer, for any environment.
+func isConfigFileName(name string) bool {
+	for _, ext := range configExtensions {
+		if strings.HasPrefix(name, baseLayer+".") && strings.HasSuffix(name, "."+ext) {
+			return true
+		}
+	}
+	return false
+}
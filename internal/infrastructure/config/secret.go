@@ -0,0 +1,138 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// redacted replaces secret values in any printed or logged output.
+const redacted = "[REDACTED]"
+
+// Secret is a sensitive configuration value (password, token, DSN with credentials).
+//
+// It redacts itself when printed with any fmt verb, encoded as JSON, YAML or text,
+// or logged through zap or slog. Call Value to get the actual secret.
+//
+// Secrets are loaded like any other setting (OPS_* env vars, config file) and can
+// also be read from a file named by the matching *_FILE env var, as used by
+// Docker and Kubernetes secrets (e.g., OPS_DATABASE_DSN_FILE=/run/secrets/dsn).
+type Secret string
+
+// Value returns the unredacted secret.
+//
+// Returns:
+//   - string: The secret value
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// String implements fmt.Stringer. Empty secrets print as empty so that
+// "not configured" stays visible.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// GoString implements fmt.GoStringer (%#v).
+func (s Secret) GoString() string {
+	return fmt.Sprintf("config.Secret(%q)", s.String())
+}
+
+// Format implements fmt.Formatter so every verb (%v, %s, %q, %x, ...) is redacted.
+func (s Secret) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		io.WriteString(f, s.GoString())
+		return
+	}
+	io.WriteString(f, s.String())
+}
+
+// MarshalText implements encoding.TextMarshaler (used by YAML encoders and zap).
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// LogValue implements slog.LogValuer.
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// secretType is the reflect.Type of Secret.
+var secretType = reflect.TypeOf(Secret(""))
+
+// loadSensitiveConfig resolves every Secret field from its *_FILE env var.
+// This ensures passwords and secrets never need to be in config files.
+//
+// The env var name is derived from the field's configuration key, e.g.
+// database.dsn is read from the file named by OPS_DATABASE_DSN_FILE.
+// Setting both OPS_DATABASE_DSN and OPS_DATABASE_DSN_FILE is an error.
+//
+// Parameters:
+//   - cfg: The configuration to update
+//
+// Returns:
+//   - error: A file that cannot be read or an ambiguous secret source
+func loadSensitiveConfig(cfg *Config) error {
+	for key, secret := range secretFields(reflect.ValueOf(cfg).Elem(), "") {
+		envName := envVar(key)
+
+		path, ok := os.LookupEnv(envName + "_FILE")
+		if !ok || path == "" {
+			continue
+		}
+		if _, set := os.LookupEnv(envName); set {
+			return fmt.Errorf("%s and %s_FILE are both set; use only one", envName, envName)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s from %s_FILE: %w", key, envName, err)
+		}
+
+		// Secret files usually end with a newline
+		*secret = Secret(strings.TrimRight(string(content), "\r\n"))
+	}
+	return nil
+}
+
+// secretFields returns pointers to all Secret fields of v keyed by their
+// configuration key (the dotted path of mapstructure tags).
+func secretFields(v reflect.Value, prefix string) map[string]*Secret {
+	fields := make(map[string]*Secret)
+
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("mapstructure")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		key := tag
+		if prefix != "" {
+			key = prefix + "." + tag
+		}
+
+		switch {
+		case field.Type == secretType:
+			fields[key] = v.Field(i).Addr().Interface().(*Secret)
+		case field.Type.Kind() == reflect.Struct:
+			for k, s := range secretFields(v.Field(i), key) {
+				fields[k] = s
+			}
+		}
+	}
+	return fields
+}
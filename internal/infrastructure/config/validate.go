@@ -0,0 +1,391 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net/netip"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Supported environments.
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+// metricNamePattern matches valid Prometheus metric name prefixes.
+var metricNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// FieldError is a single invalid configuration value.
+type FieldError struct {
+	// Key is the configuration key (e.g., "server.port")
+	Key string
+
+	// Message describes the problem
+	Message string
+}
+
+// Error implements the error interface.
+func (e FieldError) Error() string {
+	return e.Key + ": " + e.Message
+}
+
+// ValidationError reports every invalid configuration value at once.
+type ValidationError struct {
+	// Errors are the individual problems, in configuration order
+	Errors []FieldError
+}
+
+// Error implements the error interface.
+// The message lists one problem per line so it is readable in startup logs.
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	if len(e.Errors) == 1 {
+		b.WriteString("invalid configuration (1 problem):")
+	} else {
+		fmt.Fprintf(&b, "invalid configuration (%d problems):", len(e.Errors))
+	}
+	for _, fe := range e.Errors {
+		b.WriteString("\n  - ")
+		b.WriteString(fe.Error())
+	}
+	return b.String()
+}
+
+// validator collects validation problems.
+type validator struct {
+	errors []FieldError
+}
+
+// add records a problem for key.
+func (v *validator) add(key, format string, args ...any) {
+	v.errors = append(v.errors, FieldError{Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+// positive checks that a duration is greater than zero.
+func (v *validator) positive(key string, d time.Duration) {
+	if d <= 0 {
+		v.add(key, "must be a positive duration (got %s)", d)
+	}
+}
+
+// nonNegative checks that a duration is zero or greater.
+func (v *validator) nonNegative(key string, d time.Duration) {
+	if d < 0 {
+		v.add(key, "must not be negative (got %s)", d)
+	}
+}
+
+// oneOf checks that value is one of allowed.
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	if !slices.Contains(allowed, value) {
+		v.add(key, "must be one of %s (got %q)", strings.Join(allowed, ", "), value)
+	}
+}
+
+// Validate checks the configuration and returns all problems at once.
+//
+// Returns:
+//   - error: A *ValidationError listing every invalid value, or nil
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	// App
+	if strings.TrimSpace(c.App.Name) == "" {
+		v.add("app.name", "must not be empty")
+	}
+	v.oneOf("app.environment", c.App.Environment, EnvDevelopment, EnvStaging, EnvProduction)
+
+	// Server
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		v.add("server.port", "must be between 1 and 65535 (got %d)", c.Server.Port)
+	}
+	v.positive("server.read_timeout", c.Server.ReadTimeout)
+	v.positive("server.write_timeout", c.Server.WriteTimeout)
+	v.positive("server.idle_timeout", c.Server.IdleTimeout)
+	v.positive("server.shutdown_timeout", c.Server.ShutdownTimeout)
+	v.positive("server.request_timeout", c.Server.RequestTimeout)
+	if c.Server.MaxRequestSize <= 0 {
+		v.add("server.max_request_size", "must be greater than 0 (got %d)", c.Server.MaxRequestSize)
+	}
+	if len(c.Server.CORSAllowedOrigins) == 0 {
+		v.add("server.cors_allowed_origins", "must list at least one origin")
+	}
+	for _, origin := range c.Server.CORSAllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.add("server.cors_allowed_origins", "%q is not an origin (want scheme://host[:port] or \"*\")", origin)
+		}
+	}
+
+	// Log
+	v.oneOf("log.level", c.Log.Level, "debug", "info", "warn", "error")
+	v.oneOf("log.format", c.Log.Format, "json", "console")
+	if c.Log.Output == "" && len(c.Log.Sinks) == 0 {
+		v.add("log.output", "must not be empty (use stdout, stderr or a file path)")
+	}
+	if c.Log.MaxSizeMB < 0 {
+		v.add("log.max_size_mb", "must not be negative (got %d)", c.Log.MaxSizeMB)
+	}
+	v.nonNegative("log.rotation_interval", c.Log.RotationInterval)
+	if c.Log.MaxBackups < 0 {
+		v.add("log.max_backups", "must not be negative (got %d)", c.Log.MaxBackups)
+	}
+	v.nonNegative("log.max_age", c.Log.MaxAge)
+	if c.Log.SamplingInitial < 0 {
+		v.add("log.sampling_initial", "must not be negative (got %d)", c.Log.SamplingInitial)
+	}
+	if c.Log.SamplingInitial > 0 {
+		if c.Log.SamplingThereafter < 0 {
+			v.add("log.sampling_thereafter", "must not be negative (got %d)", c.Log.SamplingThereafter)
+		}
+		v.positive("log.sampling_tick", c.Log.SamplingTick)
+		v.nonNegative("log.sampling_summary_interval", c.Log.SamplingSummaryInterval)
+	}
+	for _, key := range slices.Sorted(maps.Keys(c.Log.RedactKeys)) {
+		v.oneOf("log.redact_keys."+key, c.Log.RedactKeys[key], "mask", "hash", "drop", "off")
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Log.RedactPatterns)) {
+		v.oneOf("log.redact_patterns", name, "email", "pan", "bearer")
+		v.oneOf("log.redact_patterns."+name, c.Log.RedactPatterns[name], "mask", "hash", "drop", "off")
+	}
+	sinkFiles := make(map[string]int)
+	for i, sink := range c.Log.Sinks {
+		key := fmt.Sprintf("log.sinks[%d]", i)
+		if sink.Output == "" {
+			v.add(key+".output", "must not be empty (use stdout, stderr or a file path)")
+		}
+		if sink.Output != "" && sink.Output != "stdout" && sink.Output != "stderr" {
+			// Two writers rotating the same file would corrupt it
+			path := filepath.Clean(sink.Output)
+			if j, ok := sinkFiles[path]; ok {
+				v.add(key+".output", "writes to the same file as log.sinks[%d] (got %q)", j, sink.Output)
+			} else {
+				sinkFiles[path] = i
+			}
+		}
+		if sink.Format != "" {
+			v.oneOf(key+".format", sink.Format, "json", "console")
+		}
+		if sink.Level != "" {
+			v.oneOf(key+".level", sink.Level, "debug", "info", "warn", "error")
+		}
+	}
+
+	// Request log
+	for _, path := range c.RequestLog.SkipPaths {
+		if !strings.HasPrefix(path, "/") {
+			v.add("request_log.skip_paths", "%q must start with /", path)
+		}
+	}
+	for _, path := range c.RequestLog.SamplePaths {
+		if !strings.HasPrefix(path, "/") {
+			v.add("request_log.sample_paths", "%q must start with /", path)
+		}
+	}
+	if c.RequestLog.SampleRate < 0 || c.RequestLog.SampleRate > 1 {
+		v.add("request_log.sample_rate", "must be between 0.0 and 1.0 (got %g)", c.RequestLog.SampleRate)
+	}
+	v.nonNegative("request_log.slow_threshold", c.RequestLog.SlowThreshold)
+	v.nonNegative("request_log.summary_interval", c.RequestLog.SummaryInterval)
+
+	// Database (only when configured)
+	if c.Database.DSN != "" {
+		if u, err := url.Parse(c.Database.DSN.Value()); err == nil && u.Scheme != "" &&
+			u.Scheme != "postgres" && u.Scheme != "postgresql" {
+			// Never echo the DSN: it contains credentials
+			v.add("database.dsn", "must be a postgres:// URL or a key=value connection string")
+		}
+		if c.Database.MaxConns < 1 {
+			v.add("database.max_conns", "must be at least 1 (got %d)", c.Database.MaxConns)
+		}
+		if c.Database.MinConns < 0 || c.Database.MinConns > c.Database.MaxConns {
+			v.add("database.min_conns", "must be between 0 and database.max_conns (got %d)", c.Database.MinConns)
+		}
+		v.positive("database.max_conn_lifetime", c.Database.MaxConnLifetime)
+		v.positive("database.max_conn_idle_time", c.Database.MaxConnIdleTime)
+		v.positive("database.connect_timeout", c.Database.ConnectTimeout)
+		v.nonNegative("database.query_timeout", c.Database.QueryTimeout)
+	}
+
+	// Redis (only when configured)
+	if c.Redis.Addr != "" {
+		if c.Redis.DB < 0 {
+			v.add("redis.db", "must not be negative (got %d)", c.Redis.DB)
+		}
+		if c.Redis.PoolSize < 1 {
+			v.add("redis.pool_size", "must be at least 1 (got %d)", c.Redis.PoolSize)
+		}
+		v.positive("redis.dial_timeout", c.Redis.DialTimeout)
+		v.positive("redis.read_timeout", c.Redis.ReadTimeout)
+		v.positive("redis.write_timeout", c.Redis.WriteTimeout)
+	}
+
+	// RabbitMQ (only when configured)
+	if c.RabbitMQ.URL != "" {
+		if u, err := url.Parse(c.RabbitMQ.URL.Value()); err != nil || (u.Scheme != "amqp" && u.Scheme != "amqps") {
+			// Never echo the URL: it contains credentials
+			v.add("rabbitmq.url", "must be an amqp:// or amqps:// URL")
+		}
+		if strings.TrimSpace(c.RabbitMQ.Exchange) == "" {
+			v.add("rabbitmq.exchange", "must not be empty")
+		}
+		v.positive("rabbitmq.publish_timeout", c.RabbitMQ.PublishTimeout)
+	}
+
+	// Outbox
+	v.positive("outbox.poll_interval", c.Outbox.PollInterval)
+	if c.Outbox.BatchSize < 1 {
+		v.add("outbox.batch_size", "must be at least 1 (got %d)", c.Outbox.BatchSize)
+	}
+	v.positive("outbox.lease", c.Outbox.Lease)
+	v.positive("outbox.min_backoff", c.Outbox.MinBackoff)
+	if c.Outbox.MaxBackoff < c.Outbox.MinBackoff {
+		v.add("outbox.max_backoff", "must not be less than outbox.min_backoff (got %s < %s)",
+			c.Outbox.MaxBackoff, c.Outbox.MinBackoff)
+	}
+
+	// Idempotency
+	if c.Idempotency.Enabled {
+		v.oneOf("idempotency.store", c.Idempotency.Store, "memory", "redis")
+		if c.Idempotency.Store == "redis" && c.Redis.Addr == "" {
+			v.add("idempotency.store", "\"redis\" requires redis.addr to be set")
+		}
+		v.positive("idempotency.ttl", c.Idempotency.TTL)
+		v.positive("idempotency.lock_ttl", c.Idempotency.LockTTL)
+		if c.Idempotency.LockTTL < c.Server.RequestTimeout {
+			v.add("idempotency.lock_ttl", "must not be less than server.request_timeout (got %s < %s)",
+				c.Idempotency.LockTTL, c.Server.RequestTimeout)
+		}
+	}
+
+	// Rate limiter
+	v.oneOf("rate_limit.store", c.RateLimit.Store, "memory", "redis")
+	if c.RateLimit.Store == "redis" && c.Redis.Addr == "" {
+		v.add("rate_limit.store", "\"redis\" requires redis.addr to be set")
+	}
+	if c.RateLimit.RequestsPerSecond <= 0 {
+		v.add("rate_limit.requests_per_second", "must be greater than 0 (got %g)", c.RateLimit.RequestsPerSecond)
+	}
+	if c.RateLimit.Burst < 1 {
+		v.add("rate_limit.burst", "must be at least 1 (got %d)", c.RateLimit.Burst)
+	}
+	if c.RateLimit.Store == "memory" {
+		v.positive("rate_limit.cleanup_interval", c.RateLimit.CleanupInterval)
+		v.positive("rate_limit.inactive_ttl", c.RateLimit.InactiveTTL)
+	}
+
+	// CORS
+	if len(c.CORS.AllowedMethods) == 0 {
+		v.add("cors.allowed_methods", "must list at least one method")
+	}
+	v.nonNegative("cors.max_age", c.CORS.MaxAge)
+	if c.CORS.MaxAge%time.Second != 0 {
+		v.add("cors.max_age", "must be a whole number of seconds (got %s)", c.CORS.MaxAge)
+	}
+
+	// Metrics
+	if c.Metrics.Enabled {
+		if !strings.HasPrefix(c.Metrics.Path, "/") {
+			v.add("metrics.path", "must start with \"/\" (got %q)", c.Metrics.Path)
+		}
+		if !metricNamePattern.MatchString(c.Metrics.Namespace) {
+			v.add("metrics.namespace", "must match %s (got %q)", metricNamePattern, c.Metrics.Namespace)
+		}
+	}
+
+	// Tracing
+	if c.Tracing.Enabled {
+		v.oneOf("tracing.exporter", c.Tracing.Exporter, "stdout", "file", "otlp")
+		if c.Tracing.Exporter == "file" && strings.TrimSpace(c.Tracing.FilePath) == "" {
+			v.add("tracing.file_path", "must be set when tracing.exporter is \"file\"")
+		}
+		if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+			v.add("tracing.sample_ratio", "must be between 0 and 1 (got %g)", c.Tracing.SampleRatio)
+		}
+	}
+
+	// Admin
+	if token := c.Admin.Token.Value(); token != "" && len(token) < 16 {
+		// Never echo the token
+		v.add("admin.token", "must be at least 16 characters")
+	}
+
+	// Feature flags
+	if strings.TrimSpace(c.FeatureFlags.TenantHeader) == "" {
+		v.add("feature_flags.tenant_header", "must not be empty")
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.FeatureFlags.Flags)) {
+		validateFlag(v, "feature_flags.flags."+name, c.FeatureFlags.Flags[name])
+	}
+
+	// Audit
+	if c.Audit.Path != "" {
+		// The audit trail must not be mixed with (or rotated like) the logs
+		logOutputs := []string{c.Log.Output}
+		for _, sink := range c.Log.Sinks {
+			logOutputs = append(logOutputs, sink.Output)
+		}
+		if slices.Contains(logOutputs, c.Audit.Path) {
+			v.add("audit.path", "must differ from the log outputs (got %q)", c.Audit.Path)
+		}
+	}
+	if key := c.Audit.HashKey.Value(); key != "" && len(key) < 16 {
+		// Never echo the key
+		v.add("audit.hash_key", "must be at least 16 characters")
+	}
+
+	// Environment-specific rules
+	if c.App.Environment == EnvProduction {
+		// With credentials, a wildcard would let any site make authenticated requests
+		if c.CORS.AllowCredentials && slices.Contains(c.Server.CORSAllowedOrigins, "*") {
+			v.add("server.cors_allowed_origins",
+				"must list explicit origins in production when cors.allow_credentials is true (got \"*\")")
+		}
+		if c.App.Debug {
+			v.add("app.debug", "must be false in production")
+		}
+	}
+
+	if len(v.errors) > 0 {
+		return &ValidationError{Errors: v.errors}
+	}
+	return nil
+}
+
+// validateFlag checks a single feature flag definition.
+func validateFlag(v *validator, key string, flag FeatureFlagConfig) {
+	for _, env := range flag.Environments {
+		v.oneOf(key+".environments", env, EnvDevelopment, EnvStaging, EnvProduction)
+	}
+	for _, ip := range flag.ClientIPs {
+		if _, err := netip.ParsePrefix(ip); err == nil {
+			continue
+		}
+		if _, err := netip.ParseAddr(ip); err != nil {
+			v.add(key+".client_ips", "%q is not an IP address or CIDR range", ip)
+		}
+	}
+	if p := flag.Percentage; p != nil && (*p < 0 || *p > 100) {
+		v.add(key+".percentage", "must be between 0 and 100 (got %g)", *p)
+	}
+
+	total := 0
+	for name, weight := range flag.Variants {
+		if weight < 0 {
+			v.add(key+".variants", "weight of %q must not be negative (got %d)", name, weight)
+		}
+		total += weight
+	}
+	if len(flag.Variants) > 0 && total <= 0 {
+		v.add(key+".variants", "weights must add up to more than 0")
+	}
+}
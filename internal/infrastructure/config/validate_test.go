@@ -0,0 +1,126 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validationKeys returns the keys of the problems reported by cfg.Validate.
+func validationKeys(t *testing.T, cfg *Config) []string {
+	t.Helper()
+
+	err := cfg.Validate()
+	if err == nil {
+		return nil
+	}
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+
+	keys := make([]string, len(validationErr.Errors))
+	for i, e := range validationErr.Errors {
+		keys[i] = e.Key
+	}
+	return keys
+}
+
+func TestValidate_Defaults(t *testing.T) {
+	assert.NoError(t, loadDefaults(t).Validate())
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	cfg := loadDefaults(t)
+	cfg.App.Environment = "prod"
+	cfg.Server.Port = 70000
+	cfg.Server.ReadTimeout = 0
+	cfg.Log.Level = "verbose"
+	cfg.Log.Format = "xml"
+	cfg.RateLimit.Burst = 0
+	cfg.CORS.MaxAge = 1500 * time.Millisecond
+
+	err := cfg.Validate()
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+
+	// In configuration order
+	assert.Equal(t, []FieldError{
+		{"app.environment", `must be one of development, staging, production (got "prod")`},
+		{"server.port", "must be between 1 and 65535 (got 70000)"},
+		{"server.read_timeout", "must be a positive duration (got 0s)"},
+		{"log.level", `must be one of debug, info, warn, error (got "verbose")`},
+		{"log.format", `must be one of json, console (got "xml")`},
+		{"rate_limit.burst", "must be at least 1 (got 0)"},
+		{"cors.max_age", "must be a whole number of seconds (got 1.5s)"},
+	}, validationErr.Errors)
+
+	lines := strings.Split(err.Error(), "\n")
+	assert.Equal(t, "invalid configuration (7 problems):", lines[0])
+	assert.Equal(t, "  - server.port: must be between 1 and 65535 (got 70000)", lines[2])
+	assert.Len(t, lines, 8, "one line per problem")
+}
+
+func TestValidate_SingleProblem(t *testing.T) {
+	cfg := loadDefaults(t)
+	cfg.Server.Port = 0
+
+	assert.EqualError(t, cfg.Validate(),
+		"invalid configuration (1 problem):\n  - server.port: must be between 1 and 65535 (got 0)")
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		keys   []string
+	}{
+		{"lowest port", func(c *Config) { c.Server.Port = 1 }, nil},
+		{"highest port", func(c *Config) { c.Server.Port = 65535 }, nil},
+		{"port zero", func(c *Config) { c.Server.Port = 0 }, []string{"server.port"}},
+		{"port too high", func(c *Config) { c.Server.Port = 65536 }, []string{"server.port"}},
+		{"negative write timeout", func(c *Config) { c.Server.WriteTimeout = -time.Second }, []string{"server.write_timeout"}},
+		{"zero request timeout", func(c *Config) {
+			c.Server.RequestTimeout = 0
+		}, []string{"server.request_timeout"}},
+		{"negative max age", func(c *Config) { c.Log.MaxAge = -time.Hour }, []string{"log.max_age"}},
+		{"zero max age keeps files", func(c *Config) { c.Log.MaxAge = 0 }, nil},
+		{"debug level", func(c *Config) { c.Log.Level = "debug" }, nil},
+		{"error level", func(c *Config) { c.Log.Level = "error" }, nil},
+		{"fatal level", func(c *Config) { c.Log.Level = "fatal" }, []string{"log.level"}},
+		{"empty level", func(c *Config) { c.Log.Level = "" }, []string{"log.level"}},
+		{"console format", func(c *Config) { c.Log.Format = "console" }, nil},
+		{"sink format", func(c *Config) {
+			c.Log.Sinks = []LogSinkConfig{{Output: "stdout", Format: "text", Level: "trace"}}
+		}, []string{"log.sinks[0].format", "log.sinks[0].level"}},
+		{"wildcard with credentials in production", func(c *Config) {
+			c.App.Environment = EnvProduction
+			c.Server.CORSAllowedOrigins = []string{"https://shop.example", "*"}
+			c.CORS.AllowCredentials = true
+		}, []string{"server.cors_allowed_origins"}},
+		{"wildcard without credentials in production", func(c *Config) {
+			c.App.Environment = EnvProduction
+			c.Server.CORSAllowedOrigins = []string{"*"}
+			c.CORS.AllowCredentials = false
+		}, nil},
+		{"wildcard with credentials in staging", func(c *Config) {
+			c.App.Environment = EnvStaging
+			c.Server.CORSAllowedOrigins = []string{"*"}
+			c.CORS.AllowCredentials = true
+		}, nil},
+		{"debug in production", func(c *Config) {
+			c.App.Environment = EnvProduction
+			c.Server.CORSAllowedOrigins = []string{"https://shop.example"}
+			c.App.Debug = true
+		}, []string{"app.debug"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := loadDefaults(t)
+			tt.modify(cfg)
+
+			assert.Equal(t, tt.keys, validationKeys(t, cfg))
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/hapkiduki/order-go/internal/application/port"
+)
+
+// reloadDelay groups the burst of file events an editor or a Kubernetes
+// ConfigMap update produces into a single reload.
+const reloadDelay = 200 * time.Millisecond
+
+// reloadableKeys are the settings the subscribers in main.go apply at runtime
+// (marked "reloadable" in the desc tags). A change to any other key only takes
+// effect after a restart.
+var reloadableKeys = []string{
+	"log.level",
+	"server.cors_allowed_origins",
+	"rate_limit.requests_per_second",
+	"rate_limit.burst",
+	"feature_flags.flags",
+}
+
+// Watcher reloads the configuration when the config file changes and
+// publishes each valid snapshot to its subscribers.
+//
+// Snapshots are immutable: subscribers must not modify the *Config they
+// receive. Only settings applied by a subscriber change at runtime; the
+// rest (ports, stores, timeouts...) still require a restart, and a reload
+// that changes them logs a warning naming them.
+type Watcher struct {
+	current atomic.Pointer[Config]
+	logger  port.Logger
+	fsw     *fsnotify.Watcher
+
+	// initial is the configuration the process started with, which the
+	// settings that are not reloaded still hold
+	initial *Config
+
+	// reloadMu serializes reloads so snapshots are published in order
+	reloadMu sync.Mutex
+
+	mu          sync.Mutex
+	subscribers []func(*Config)
+	timer       *time.Timer
+}
+
+// Watch starts watching the config file layers read by Load: every
+// config*.yaml file in the config directories, including files created later.
+//
+// Invalid reloads (unreadable YAML, failed validation) are rejected and
+// logged, and the last good configuration is kept.
+//
+// Parameters:
+//   - initial: The configuration returned by Load at startup
+//   - logger: Logger for reload results
+//
+// Returns:
+//   - *Watcher: The watcher (call Close to stop watching)
+//   - error: If the config directories cannot be watched
+func Watch(initial *Config, logger port.Logger) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch config files: %w", err)
+	}
+
+	// Watch directories rather than files: editors and Kubernetes ConfigMaps
+	// replace files instead of writing to them
+	var dirs []string
+	for _, dir := range configPaths {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		if err := fsw.Add(dir); err != nil {
+			fsw.Close()
+			return nil, fmt.Errorf("failed to watch config directory %s: %w", dir, err)
+		}
+		dirs = append(dirs, dir)
+	}
+
+	w := &Watcher{logger: logger, fsw: fsw, initial: initial}
+	w.current.Store(initial)
+	go w.run()
+
+	logger.Info("Watching config files for changes", "dirs", dirs)
+	return w, nil
+}
+
+// Close stops watching the config files.
+//
+// Returns:
+//   - error: Any error closing the file watcher
+func (w *Watcher) Close() error {
+	w.mu.Lock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mu.Unlock()
+
+	return w.fsw.Close()
+}
+
+// run schedules a reload for every change to a config file layer.
+func (w *Watcher) run() {
+	for {
+		select {
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+
+			// "..data" is the symlink Kubernetes swaps when a ConfigMap changes
+			name := filepath.Base(event.Name)
+			if isConfigFileName(name) || strings.HasPrefix(name, "..") {
+				w.scheduleReload()
+			}
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			w.logger.Warn("Config file watcher error", "error", err)
+		}
+	}
+}
+
+// scheduleReload reloads after reloadDelay, restarting the delay on every call.
+func (w *Watcher) scheduleReload() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.timer = time.AfterFunc(reloadDelay, w.reload)
+}
+
+// Current returns the last valid configuration.
+//
+// Returns:
+//   - *Config: The current configuration snapshot
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Subscribe registers fn to be called with every new valid configuration.
+// Subscribers are called one at a time, in registration order.
+//
+// Parameters:
+//   - fn: The function receiving the new configuration
+func (w *Watcher) Subscribe(fn func(*Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// reload loads the configuration again and publishes it if it is valid.
+func (w *Watcher) reload() {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	// Load again from scratch so env vars and *_FILE secrets still take
+	// precedence, exactly as at startup
+	cfg, err := Load()
+	if err != nil {
+		w.logger.Error("Configuration reload rejected, keeping last good configuration", "error", err)
+		return
+	}
+
+	w.current.Store(cfg)
+
+	w.mu.Lock()
+	subscribers := slices.Clone(w.subscribers)
+	w.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(cfg)
+	}
+
+	w.logger.Info("Configuration reloaded",
+		"log_level", cfg.Log.Level,
+		"rate_limit_rps", cfg.RateLimit.RequestsPerSecond,
+		"rate_limit_burst", cfg.RateLimit.Burst,
+		"cors_allowed_origins", cfg.Server.CORSAllowedOrigins,
+		"feature_flags", len(cfg.FeatureFlags.Flags),
+	)
+	w.warnRestart(cfg)
+}
+
+// warnRestart logs the settings of cfg that differ from the running ones but
+// are not reloaded. It warns on every reload until the process is restarted.
+func (w *Watcher) warnRestart(cfg *Config) {
+	if keys := restartKeys(w.initial, cfg); len(keys) > 0 {
+		w.logger.Warn("Configuration changes need a restart to take effect", "keys", keys)
+	}
+}
+
+// restartKeys returns the keys whose value differs between running and cfg,
+// except the reloadable ones.
+//
+// Parameters:
+//   - running: The configuration the process started with
+//   - cfg: The reloaded configuration
+//
+// Returns:
+//   - []string: The changed keys, in configuration order
+func restartKeys(running, cfg *Config) []string {
+	before := sections(running)
+
+	var keys []string
+	for i, s := range sections(cfg) {
+		for j, f := range s.fields {
+			if slices.Contains(reloadableKeys, f.key) {
+				continue
+			}
+			if !reflect.DeepEqual(before[i].fields[j].value.Interface(), f.value.Interface()) {
+				keys = append(keys, f.key)
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/infrastructure/logging"
+	"github.com/hapkiduki/order-go/pkg/logger/loggertest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadDefaults(t *testing.T) *Config {
+	t.Helper()
+
+	unsetEnv(t)
+	cfg, err := LoadFS(afero.NewMemMapFs())
+	require.NoError(t, err)
+	return cfg
+}
+
+func TestRestartKeys(t *testing.T) {
+	running := loadDefaults(t)
+
+	cfg := loadDefaults(t)
+	assert.Empty(t, restartKeys(running, cfg))
+
+	// Reloadable settings are applied at runtime
+	cfg.Log.Level = "debug"
+	cfg.RateLimit.Burst = 500
+	cfg.Server.CORSAllowedOrigins = []string{"https://shop.example"}
+	cfg.FeatureFlags.Flags = map[string]FeatureFlagConfig{"new_checkout": {Enabled: true}}
+	assert.Empty(t, restartKeys(running, cfg))
+
+	cfg.Server.Port = 9090
+	cfg.Database.DSN = Secret("postgres://order@db/orders")
+	cfg.Idempotency.LockTTL = time.Hour
+	assert.Equal(t, []string{"server.port", "database.dsn", "idempotency.lock_ttl"}, restartKeys(running, cfg))
+}
+
+func TestReloadableKeysMatchDesc(t *testing.T) {
+	var marked []string
+	for _, s := range sections(loadDefaults(t)) {
+		for _, f := range s.fields {
+			if strings.Contains(f.tag.Tag.Get("desc"), "(reloadable)") {
+				marked = append(marked, f.key)
+			}
+		}
+	}
+
+	// feature_flags.flags is marked on its section
+	assert.ElementsMatch(t, reloadableKeys, append(marked, "feature_flags.flags"))
+}
+
+func TestWatcher_WarnRestart(t *testing.T) {
+	rec := loggertest.New()
+	w := &Watcher{logger: logging.New(rec.Logger()), initial: loadDefaults(t)}
+
+	cfg := loadDefaults(t)
+	cfg.Log.Level = "debug"
+	w.warnRestart(cfg)
+	rec.AssertNoEntry(t, "warn", "")
+
+	cfg.Server.Port = 9090
+	cfg.Server.RequestTimeout = time.Minute
+	w.warnRestart(cfg)
+	entry := rec.RequireEntry(t, "warn", "Configuration changes need a restart to take effect")
+	entry.AssertField(t, "keys", []any{"server.port", "server.request_timeout"})
+}
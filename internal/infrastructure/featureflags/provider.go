@@ -0,0 +1,272 @@
+// Package featureflags provides a config-backed implementation of port.FeatureFlags.
+//
+// Flags are defined in the feature_flags.flags config section and can be
+// replaced at runtime (e.g., on configuration reload) with Provider.Set.
+// Percentage rollouts and variants hash the flag name with a stable request
+// attribute (user ID, then tenant ID, then client IP), so a given user keeps
+// the same result across requests and replicas, and users already in a
+// rollout stay in when the percentage is raised.
+package featureflags
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"net/netip"
+	"slices"
+	"sync/atomic"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/infrastructure/config"
+	"github.com/hapkiduki/order-go/pkg/ctxkeys"
+)
+
+// buckets is the rollout resolution: percentages are honoured to 0.01%.
+const buckets = 10000
+
+// flag is a compiled feature flag definition.
+type flag struct {
+	description  string
+	enabled      bool
+	environments []string
+	tenants      []string
+	users        []string
+	prefixes     []netip.Prefix
+	percentage   float64
+	variants     []variant
+	totalWeight  int
+}
+
+// variant is a weighted variant of a multivariate flag.
+type variant struct {
+	name   string
+	weight int
+}
+
+// Provider is a config-backed implementation of port.FeatureFlags.
+// It is safe for concurrent use; Set swaps all flags at once.
+type Provider struct {
+	flags atomic.Pointer[map[string]*flag]
+}
+
+// Compile-time check that Provider implements port.FeatureFlags.
+var _ port.FeatureFlags = (*Provider)(nil)
+
+// NewProvider creates a provider with the given flag definitions.
+//
+// Parameters:
+//   - flags: The flag definitions keyed by flag name
+//
+// Returns:
+//   - *Provider: The provider
+//   - error: If a definition is invalid
+func NewProvider(flags map[string]config.FeatureFlagConfig) (*Provider, error) {
+	p := &Provider{}
+	if err := p.Set(flags); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Set replaces all flag definitions. On error the current flags are kept.
+//
+// Parameters:
+//   - flags: The flag definitions keyed by flag name
+//
+// Returns:
+//   - error: If a definition is invalid
+func (p *Provider) Set(flags map[string]config.FeatureFlagConfig) error {
+	compiled := make(map[string]*flag, len(flags))
+	for name, def := range flags {
+		f, err := compile(def)
+		if err != nil {
+			return fmt.Errorf("feature flag %s: %w", name, err)
+		}
+		compiled[name] = f
+	}
+
+	p.flags.Store(&compiled)
+	return nil
+}
+
+// IsEnabled implements port.FeatureFlags.
+func (p *Provider) IsEnabled(ctx context.Context, key string) bool {
+	return p.Evaluate(key, flagContext(ctx)).Enabled
+}
+
+// Variant implements port.FeatureFlags.
+func (p *Provider) Variant(ctx context.Context, key, fallback string) string {
+	if v := p.Evaluate(key, flagContext(ctx)).Variant; v != "" {
+		return v
+	}
+	return fallback
+}
+
+// Evaluate implements port.FeatureFlags.
+func (p *Provider) Evaluate(key string, fc port.FlagContext) port.FlagEvaluation {
+	f, ok := (*p.flags.Load())[key]
+	if !ok {
+		return port.FlagEvaluation{Key: key, Reason: port.FlagReasonNotFound}
+	}
+
+	eval := f.evaluate(key, fc)
+	eval.Key = key
+	eval.Description = f.description
+	return eval
+}
+
+// EvaluateAll evaluates every flag against fc.
+//
+// Parameters:
+//   - fc: The attributes to evaluate against
+//
+// Returns:
+//   - []port.FlagEvaluation: One evaluation per flag, sorted by name
+func (p *Provider) EvaluateAll(fc port.FlagContext) []port.FlagEvaluation {
+	flags := *p.flags.Load()
+
+	evals := make([]port.FlagEvaluation, 0, len(flags))
+	for _, key := range slices.Sorted(maps.Keys(flags)) {
+		evals = append(evals, p.Evaluate(key, fc))
+	}
+	return evals
+}
+
+// evaluate applies the flag rules to fc.
+func (f *flag) evaluate(key string, fc port.FlagContext) port.FlagEvaluation {
+	if !f.enabled {
+		return port.FlagEvaluation{Reason: port.FlagReasonDisabled}
+	}
+	if len(f.environments) > 0 && !slices.Contains(f.environments, fc.Environment) {
+		return port.FlagEvaluation{Reason: port.FlagReasonEnvironment}
+	}
+
+	stickyKey := firstNonEmpty(fc.UserID, fc.TenantID, fc.ClientIP)
+
+	reason := port.FlagReasonDefault
+	switch {
+	case f.targets(fc):
+		reason = port.FlagReasonTarget
+	case f.percentage < 100:
+		if float64(bucket(key, stickyKey)) >= f.percentage*buckets/100 {
+			return port.FlagEvaluation{Reason: port.FlagReasonRollout}
+		}
+		reason = port.FlagReasonRollout
+	}
+
+	return port.FlagEvaluation{
+		Enabled: true,
+		Variant: f.variant(key, stickyKey),
+		Reason:  reason,
+	}
+}
+
+// targets reports whether fc is explicitly targeted by tenant, user or client IP.
+func (f *flag) targets(fc port.FlagContext) bool {
+	if fc.TenantID != "" && slices.Contains(f.tenants, fc.TenantID) {
+		return true
+	}
+	if fc.UserID != "" && slices.Contains(f.users, fc.UserID) {
+		return true
+	}
+	if len(f.prefixes) > 0 {
+		if addr, err := netip.ParseAddr(fc.ClientIP); err == nil {
+			for _, prefix := range f.prefixes {
+				if prefix.Contains(addr.Unmap()) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// variant picks a weighted variant for stickyKey, or "" for boolean flags.
+func (f *flag) variant(key, stickyKey string) string {
+	if f.totalWeight == 0 {
+		return ""
+	}
+
+	// A different salt than the rollout keeps both choices independent
+	point := int(bucket(key+"/variant", stickyKey)) * f.totalWeight / buckets
+	for _, v := range f.variants {
+		if point < v.weight {
+			return v.name
+		}
+		point -= v.weight
+	}
+	return f.variants[len(f.variants)-1].name
+}
+
+// compile validates and prepares a flag definition for evaluation.
+func compile(def config.FeatureFlagConfig) (*flag, error) {
+	f := &flag{
+		description:  def.Description,
+		enabled:      def.Enabled,
+		environments: def.Environments,
+		tenants:      def.Tenants,
+		users:        def.Users,
+		percentage:   100,
+	}
+
+	if def.Percentage != nil {
+		if *def.Percentage < 0 || *def.Percentage > 100 {
+			return nil, fmt.Errorf("percentage must be between 0 and 100 (got %g)", *def.Percentage)
+		}
+		f.percentage = *def.Percentage
+	}
+
+	for _, ip := range def.ClientIPs {
+		prefix, err := netip.ParsePrefix(ip)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(ip)
+			if addrErr != nil {
+				return nil, fmt.Errorf("%q is not an IP address or CIDR range", ip)
+			}
+			addr = addr.Unmap()
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		f.prefixes = append(f.prefixes, prefix.Masked())
+	}
+
+	// Sorted names make the variant assignment independent of map order
+	for _, name := range slices.Sorted(maps.Keys(def.Variants)) {
+		weight := def.Variants[name]
+		if weight < 0 {
+			return nil, fmt.Errorf("weight of variant %q must not be negative", name)
+		}
+		f.variants = append(f.variants, variant{name: name, weight: weight})
+		f.totalWeight += weight
+	}
+
+	return f, nil
+}
+
+// bucket hashes key and stickyKey into [0, buckets).
+func bucket(key, stickyKey string) uint64 {
+	sum := sha256.Sum256([]byte(key + ":" + stickyKey))
+	return binary.BigEndian.Uint64(sum[:8]) % buckets
+}
+
+// flagContext returns the attributes carried by ctx. The user ID is read at
+// evaluation time because authentication may run after the middleware that
+// stored the FlagContext.
+func flagContext(ctx context.Context) port.FlagContext {
+	fc := port.FlagContextFrom(ctx)
+	if fc.UserID == "" {
+		fc.UserID = ctxkeys.Get(ctx, ctxkeys.UserID)
+	}
+	return fc
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,284 @@
+package featureflags
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/infrastructure/config"
+	"github.com/hapkiduki/order-go/pkg/ctxkeys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProvider(t *testing.T, flags map[string]config.FeatureFlagConfig) *Provider {
+	t.Helper()
+
+	p, err := NewProvider(flags)
+	require.NoError(t, err)
+	return p
+}
+
+func percentage(p float64) *float64 {
+	return &p
+}
+
+// enabledUsers counts how many of n distinct users get the flag.
+func enabledUsers(p *Provider, key string, n int) int {
+	enabled := 0
+	for i := range n {
+		if p.Evaluate(key, port.FlagContext{UserID: fmt.Sprintf("user-%d", i)}).Enabled {
+			enabled++
+		}
+	}
+	return enabled
+}
+
+func TestProvider_NotFound(t *testing.T) {
+	p := newTestProvider(t, nil)
+
+	assert.Equal(t, port.FlagEvaluation{Key: "missing", Reason: port.FlagReasonNotFound},
+		p.Evaluate("missing", port.FlagContext{UserID: "u1"}))
+	assert.False(t, p.IsEnabled(context.Background(), "missing"))
+	assert.Equal(t, "control", p.Variant(context.Background(), "missing", "control"))
+}
+
+func TestProvider_Disabled(t *testing.T) {
+	p := newTestProvider(t, map[string]config.FeatureFlagConfig{
+		"checkout": {Enabled: false, Users: []string{"u1"}},
+	})
+
+	eval := p.Evaluate("checkout", port.FlagContext{UserID: "u1"})
+	assert.False(t, eval.Enabled, "targeting does not override the switch")
+	assert.Equal(t, port.FlagReasonDisabled, eval.Reason)
+}
+
+func TestProvider_Percentage(t *testing.T) {
+	const users = 10000
+
+	tests := []struct {
+		name       string
+		percentage *float64
+		min, max   int
+	}{
+		{"unset", nil, users, users},
+		{"zero", percentage(0), 0, 0},
+		{"hundred", percentage(100), users, users},
+		{"ten", percentage(10), 900, 1100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProvider(t, map[string]config.FeatureFlagConfig{
+				"checkout": {Enabled: true, Percentage: tt.percentage},
+			})
+
+			enabled := enabledUsers(p, "checkout", users)
+			assert.GreaterOrEqual(t, enabled, tt.min)
+			assert.LessOrEqual(t, enabled, tt.max)
+		})
+	}
+}
+
+func TestProvider_StickyBucketing(t *testing.T) {
+	p := newTestProvider(t, map[string]config.FeatureFlagConfig{
+		"checkout": {Enabled: true, Percentage: percentage(50)},
+	})
+
+	for i := range 100 {
+		fc := port.FlagContext{UserID: fmt.Sprintf("user-%d", i)}
+		first := p.Evaluate("checkout", fc)
+		for range 10 {
+			assert.Equal(t, first, p.Evaluate("checkout", fc), "user %s", fc.UserID)
+		}
+	}
+
+	// A replica loading the same definitions agrees
+	replica := newTestProvider(t, map[string]config.FeatureFlagConfig{
+		"checkout": {Enabled: true, Percentage: percentage(50)},
+	})
+	for i := range 100 {
+		fc := port.FlagContext{UserID: fmt.Sprintf("user-%d", i)}
+		assert.Equal(t, p.Evaluate("checkout", fc), replica.Evaluate("checkout", fc))
+	}
+}
+
+func TestProvider_RaisingPercentageKeepsUsers(t *testing.T) {
+	p := newTestProvider(t, map[string]config.FeatureFlagConfig{
+		"checkout": {Enabled: true, Percentage: percentage(10)},
+	})
+	var before []string
+	for i := range 1000 {
+		user := fmt.Sprintf("user-%d", i)
+		if p.Evaluate("checkout", port.FlagContext{UserID: user}).Enabled {
+			before = append(before, user)
+		}
+	}
+	require.NotEmpty(t, before)
+
+	require.NoError(t, p.Set(map[string]config.FeatureFlagConfig{
+		"checkout": {Enabled: true, Percentage: percentage(30)},
+	}))
+	for _, user := range before {
+		assert.True(t, p.Evaluate("checkout", port.FlagContext{UserID: user}).Enabled, "user %s", user)
+	}
+}
+
+func TestProvider_Variants(t *testing.T) {
+	const users = 10000
+	p := newTestProvider(t, map[string]config.FeatureFlagConfig{
+		"pricing": {Enabled: true, Variants: map[string]int{"control": 70, "discount": 20, "bundle": 10, "never": 0}},
+	})
+
+	counts := map[string]int{}
+	for i := range users {
+		counts[p.Evaluate("pricing", port.FlagContext{UserID: fmt.Sprintf("user-%d", i)}).Variant]++
+	}
+
+	assert.InDelta(t, 7000, counts["control"], 200)
+	assert.InDelta(t, 2000, counts["discount"], 200)
+	assert.InDelta(t, 1000, counts["bundle"], 200)
+	assert.Zero(t, counts["never"], "a zero weight is never picked")
+	assert.Zero(t, counts[""])
+
+	// The assignment does not depend on map iteration order
+	for i := range 100 {
+		fc := port.FlagContext{UserID: fmt.Sprintf("user-%d", i)}
+		reordered := newTestProvider(t, map[string]config.FeatureFlagConfig{
+			"pricing": {Enabled: true, Variants: map[string]int{"never": 0, "bundle": 10, "discount": 20, "control": 70}},
+		})
+		assert.Equal(t, p.Evaluate("pricing", fc).Variant, reordered.Evaluate("pricing", fc).Variant)
+	}
+}
+
+func TestProvider_VariantOff(t *testing.T) {
+	p := newTestProvider(t, map[string]config.FeatureFlagConfig{
+		"pricing": {Enabled: true, Percentage: percentage(0), Variants: map[string]int{"discount": 1}},
+		"banner":  {Enabled: true},
+	})
+	ctx := ctxkeys.With(context.Background(), ctxkeys.UserID, "u1")
+
+	assert.Equal(t, "control", p.Variant(ctx, "pricing", "control"), "off")
+	assert.Equal(t, "control", p.Variant(ctx, "banner", "control"), "no variants")
+}
+
+func TestProvider_StickyKeyFallback(t *testing.T) {
+	p := newTestProvider(t, map[string]config.FeatureFlagConfig{
+		"checkout": {Enabled: true, Percentage: percentage(50)},
+	})
+	f := (*p.flags.Load())["checkout"]
+
+	// wantEnabled is the rollout decision for stickyKey alone
+	wantEnabled := func(stickyKey string) bool {
+		return float64(bucket("checkout", stickyKey)) < f.percentage*buckets/100
+	}
+
+	for i := range 50 {
+		user, tenant, ip := fmt.Sprintf("user-%d", i), fmt.Sprintf("tenant-%d", i), fmt.Sprintf("10.0.%d.1", i)
+
+		assert.Equal(t, wantEnabled(user),
+			p.Evaluate("checkout", port.FlagContext{UserID: user, TenantID: "t1", ClientIP: "10.0.0.1"}).Enabled, "user %s", user)
+		assert.Equal(t, wantEnabled(tenant),
+			p.Evaluate("checkout", port.FlagContext{TenantID: tenant, ClientIP: "10.0.0.1"}).Enabled, "tenant %s", tenant)
+		assert.Equal(t, wantEnabled(ip),
+			p.Evaluate("checkout", port.FlagContext{ClientIP: ip}).Enabled, "ip %s", ip)
+	}
+
+	// The same user gets the same result from any tenant or IP
+	results := map[bool]bool{}
+	for i := range 50 {
+		fc := port.FlagContext{UserID: "u1", TenantID: fmt.Sprintf("t%d", i), ClientIP: fmt.Sprintf("10.0.%d.1", i)}
+		results[p.Evaluate("checkout", fc).Enabled] = true
+	}
+	assert.Len(t, results, 1)
+}
+
+func TestProvider_UserFromContext(t *testing.T) {
+	p := newTestProvider(t, map[string]config.FeatureFlagConfig{
+		"checkout": {Enabled: true, Users: []string{"u1"}, Percentage: percentage(0)},
+	})
+
+	ctx := port.WithFlagContext(context.Background(), port.FlagContext{ClientIP: "10.0.0.1"})
+	assert.False(t, p.IsEnabled(ctx, "checkout"))
+
+	// Authentication runs after the flag middleware
+	assert.True(t, p.IsEnabled(ctxkeys.With(ctx, ctxkeys.UserID, "u1"), "checkout"))
+}
+
+func TestProvider_Rules(t *testing.T) {
+	p := newTestProvider(t, map[string]config.FeatureFlagConfig{
+		"checkout": {
+			Enabled:      true,
+			Environments: []string{"staging", "production"},
+			Tenants:      []string{"acme"},
+			Users:        []string{"u1"},
+			ClientIPs:    []string{"10.1.0.0/16", "192.168.0.7", "2001:db8::/32"},
+			Percentage:   percentage(0),
+		},
+		"everyone": {Enabled: true},
+	})
+
+	tests := []struct {
+		name   string
+		flag   string
+		fc     port.FlagContext
+		want   bool
+		reason string
+	}{
+		{"other environment", "checkout", port.FlagContext{Environment: "development", TenantID: "acme"}, false, port.FlagReasonEnvironment},
+		{"no environment", "checkout", port.FlagContext{TenantID: "acme"}, false, port.FlagReasonEnvironment},
+		{"targeted tenant", "checkout", port.FlagContext{Environment: "staging", TenantID: "acme"}, true, port.FlagReasonTarget},
+		{"other tenant", "checkout", port.FlagContext{Environment: "staging", TenantID: "globex"}, false, port.FlagReasonRollout},
+		{"targeted user in other tenant", "checkout", port.FlagContext{Environment: "production", TenantID: "globex", UserID: "u1"}, true, port.FlagReasonTarget},
+		{"ip in range", "checkout", port.FlagContext{Environment: "production", ClientIP: "10.1.2.3"}, true, port.FlagReasonTarget},
+		{"ip outside range", "checkout", port.FlagContext{Environment: "production", ClientIP: "10.2.0.1"}, false, port.FlagReasonRollout},
+		{"single ip", "checkout", port.FlagContext{Environment: "production", ClientIP: "192.168.0.7"}, true, port.FlagReasonTarget},
+		{"ipv4-mapped ip", "checkout", port.FlagContext{Environment: "production", ClientIP: "::ffff:192.168.0.7"}, true, port.FlagReasonTarget},
+		{"ipv6 range", "checkout", port.FlagContext{Environment: "production", ClientIP: "2001:db8::1"}, true, port.FlagReasonTarget},
+		{"invalid ip", "checkout", port.FlagContext{Environment: "production", ClientIP: "unknown"}, false, port.FlagReasonRollout},
+		{"no rules", "everyone", port.FlagContext{}, true, port.FlagReasonDefault},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eval := p.Evaluate(tt.flag, tt.fc)
+
+			assert.Equal(t, tt.want, eval.Enabled)
+			assert.Equal(t, tt.reason, eval.Reason)
+		})
+	}
+}
+
+func TestProvider_InvalidDefinitions(t *testing.T) {
+	tests := []struct {
+		name string
+		def  config.FeatureFlagConfig
+		err  string
+	}{
+		{"negative percentage", config.FeatureFlagConfig{Percentage: percentage(-1)}, "percentage must be between 0 and 100"},
+		{"percentage above 100", config.FeatureFlagConfig{Percentage: percentage(100.5)}, "percentage must be between 0 and 100"},
+		{"invalid ip", config.FeatureFlagConfig{ClientIPs: []string{"10.0.0.300"}}, `"10.0.0.300" is not an IP address or CIDR range`},
+		{"negative weight", config.FeatureFlagConfig{Variants: map[string]int{"a": -1}}, `weight of variant "a" must not be negative`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewProvider(map[string]config.FeatureFlagConfig{"checkout": tt.def})
+
+			assert.ErrorContains(t, err, "feature flag checkout: "+tt.err)
+		})
+	}
+}
+
+func TestProvider_SetKeepsFlagsOnError(t *testing.T) {
+	p := newTestProvider(t, map[string]config.FeatureFlagConfig{
+		"checkout": {Enabled: true, Description: "New checkout"},
+	})
+
+	err := p.Set(map[string]config.FeatureFlagConfig{
+		"checkout": {Enabled: true, Percentage: percentage(200)},
+	})
+	require.Error(t, err)
+
+	evals := p.EvaluateAll(port.FlagContext{})
+	require.Len(t, evals, 1)
+	assert.Equal(t, port.FlagEvaluation{Key: "checkout", Description: "New checkout", Enabled: true, Reason: port.FlagReasonDefault}, evals[0])
+}
@@ -0,0 +1,252 @@
+// Package health provides a registry of dependency health checks used by
+// the readiness endpoint.
+//
+// Kubernetes uses readiness probes to decide whether a pod may receive traffic.
+// The registry runs every registered port.HealthChecker in parallel, each with its
+// own timeout, and aggregates the results into a single report.
+package health
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+)
+
+// DefaultTimeout is the per-check timeout used when none is configured.
+const DefaultTimeout = 2 * time.Second
+
+// CheckReport is the result of a single dependency check.
+type CheckReport struct {
+	// Name is the dependency name
+	Name string `json:"name"`
+
+	// Status is the dependency status (ok, degraded, down)
+	Status port.HealthStatus `json:"status"`
+
+	// Critical reports whether a failure of this check makes the service not ready
+	Critical bool `json:"critical"`
+
+	// LatencyMS is how long the check took, in milliseconds
+	LatencyMS int64 `json:"latency_ms"`
+
+	// Error is the error of the current run, if any
+	Error string `json:"error,omitempty"`
+
+	// LastError is the most recent error seen for this check across runs
+	LastError string `json:"last_error,omitempty"`
+
+	// LastErrorAt is when LastError was observed
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// Report is the aggregated result of all checks.
+type Report struct {
+	// Status is the overall status: down if any critical check is down,
+	// degraded if any check is not ok, ok otherwise
+	Status port.HealthStatus `json:"status"`
+
+	// Checks contains one entry per registered check, sorted by name
+	Checks []CheckReport `json:"checks"`
+}
+
+// Ready reports whether the service can take traffic.
+func (r Report) Ready() bool {
+	return r.Status != port.HealthStatusDown
+}
+
+// Option configures a registered check.
+type Option func(*entry)
+
+// Critical marks the check as critical. A failing critical check makes the
+// whole service not ready.
+func Critical() Option {
+	return func(e *entry) {
+		e.critical = true
+	}
+}
+
+// WithTimeout sets the per-check timeout.
+//
+// Parameters:
+//   - timeout: Maximum duration for the check
+func WithTimeout(timeout time.Duration) Option {
+	return func(e *entry) {
+		if timeout > 0 {
+			e.timeout = timeout
+		}
+	}
+}
+
+// entry holds a registered checker and its last known error.
+type entry struct {
+	checker  port.HealthChecker
+	critical bool
+	timeout  time.Duration
+
+	mu          sync.Mutex
+	lastError   string
+	lastErrorAt time.Time
+}
+
+// Registry holds the registered health checks.
+// It is safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	entries map[string]*entry
+}
+
+// NewRegistry creates an empty health check registry.
+//
+// Returns:
+//   - *Registry: The registry
+func NewRegistry() *Registry {
+	return &Registry{
+		entries: make(map[string]*entry),
+	}
+}
+
+// Register adds a checker to the registry. A checker with the same name replaces the previous one.
+// Checks are non-critical with DefaultTimeout unless options say otherwise.
+//
+// Parameters:
+//   - checker: The dependency checker
+//   - opts: Optional settings (Critical, WithTimeout)
+func (r *Registry) Register(checker port.HealthChecker, opts ...Option) {
+	e := &entry{
+		checker: checker,
+		timeout: DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[checker.Name()] = e
+}
+
+// Check runs all registered checks in parallel and returns the aggregated report.
+//
+// Parameters:
+//   - ctx: Parent context; each check gets its own timeout derived from it
+//
+// Returns:
+//   - Report: The aggregated health report
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.RLock()
+	entries := make([]*entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		entries = append(entries, e)
+	}
+	r.mu.RUnlock()
+
+	results := make([]CheckReport, len(entries))
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func(i int, e *entry) {
+			defer wg.Done()
+			results[i] = e.run(ctx)
+		}(i, e)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	report := Report{Status: port.HealthStatusOK, Checks: results}
+	for _, res := range results {
+		switch {
+		case res.Status == port.HealthStatusDown && res.Critical:
+			report.Status = port.HealthStatusDown
+		case res.Status != port.HealthStatusOK && report.Status == port.HealthStatusOK:
+			report.Status = port.HealthStatusDegraded
+		}
+	}
+
+	return report
+}
+
+// run executes a single check with its timeout and records the outcome.
+func (e *entry) run(ctx context.Context) CheckReport {
+	checkCtx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := e.safeCheck(checkCtx)
+	latency := time.Since(start)
+
+	res := CheckReport{
+		Name:      e.checker.Name(),
+		Status:    port.HealthStatusOK,
+		Critical:  e.critical,
+		LatencyMS: latency.Milliseconds(),
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err != nil {
+		res.Error = err.Error()
+		if errors.Is(err, port.ErrDegraded) {
+			res.Status = port.HealthStatusDegraded
+		} else {
+			res.Status = port.HealthStatusDown
+		}
+		e.lastError = res.Error
+		e.lastErrorAt = time.Now().UTC()
+	}
+
+	if e.lastError != "" {
+		lastErrorAt := e.lastErrorAt
+		res.LastError = e.lastError
+		res.LastErrorAt = &lastErrorAt
+	}
+
+	return res
+}
+
+// safeCheck runs the checker, converting timeouts and panics into errors.
+// The checker runs in its own goroutine so a check that ignores ctx cannot block the report.
+func (e *entry) safeCheck(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				done <- errors.New("health check panicked")
+			}
+		}()
+		done <- e.checker.Check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CheckerFunc adapts a plain function to the port.HealthChecker interface.
+type CheckerFunc struct {
+	// CheckName is the dependency name
+	CheckName string
+
+	// Fn performs the check
+	Fn func(ctx context.Context) error
+}
+
+// Name implements port.HealthChecker.
+func (c CheckerFunc) Name() string {
+	return c.CheckName
+}
+
+// Check implements port.HealthChecker.
+func (c CheckerFunc) Check(ctx context.Context) error {
+	return c.Fn(ctx)
+}
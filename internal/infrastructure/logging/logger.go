@@ -0,0 +1,63 @@
+// Package logging provides implementations of the port.Logger interface.
+//
+// The Logger adapter writes to a pkg/logger Logger, the application logger
+// configured in main.go. The SlogLogger adapter writes to any log/slog
+// logger (e.g., when a library provides the slog logger the application
+// must use).
+package logging
+
+import (
+	"context"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/pkg/logger"
+)
+
+// Logger adapts a logger.Logger to the port.Logger interface.
+type Logger struct {
+	*logger.Logger
+}
+
+// Compile-time check that Logger implements port.Logger.
+var _ port.Logger = (*Logger)(nil)
+
+// New creates a port.Logger writing to l.
+//
+// Parameters:
+//   - l: The logger to write to
+//
+// Returns:
+//   - *Logger: The logger
+func New(l *logger.Logger) *Logger {
+	return &Logger{l}
+}
+
+// Debug implements port.Logger.
+func (l *Logger) Debug(msg string, keysAndValues ...any) {
+	l.Logger.Debug(msg, keysAndValues...)
+}
+
+// Info implements port.Logger.
+func (l *Logger) Info(msg string, keysAndValues ...any) {
+	l.Logger.Info(msg, keysAndValues...)
+}
+
+// Warn implements port.Logger.
+func (l *Logger) Warn(msg string, keysAndValues ...any) {
+	l.Logger.Warn(msg, keysAndValues...)
+}
+
+// Error implements port.Logger.
+func (l *Logger) Error(msg string, keysAndValues ...any) {
+	l.Logger.Error(msg, keysAndValues...)
+}
+
+// With implements port.Logger.
+func (l *Logger) With(keysAndValues ...any) port.Logger {
+	return &Logger{l.Logger.With(keysAndValues...)}
+}
+
+// WithContext implements port.Logger.
+func (l *Logger) WithContext(ctx context.Context) port.Logger {
+	return &Logger{l.Logger.WithContext(ctx)}
+}
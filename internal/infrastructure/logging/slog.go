@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/pkg/logger"
+)
+
+// SlogLogger adapts any slog.Logger to the port.Logger interface.
+type SlogLogger struct {
+	logger *slog.Logger
+	ctx    context.Context
+}
+
+// Compile-time check that SlogLogger implements port.Logger.
+var _ port.Logger = (*SlogLogger)(nil)
+
+// NewSlogLogger creates a port.Logger writing to l.
+//
+// Parameters:
+//   - l: The slog logger to write to
+//
+// Returns:
+//   - *SlogLogger: The logger
+func NewSlogLogger(l *slog.Logger) *SlogLogger {
+	return &SlogLogger{logger: l, ctx: context.Background()}
+}
+
+// Debug implements port.Logger.
+func (l *SlogLogger) Debug(msg string, keysAndValues ...any) {
+	l.log(slog.LevelDebug, msg, keysAndValues)
+}
+
+// Info implements port.Logger.
+func (l *SlogLogger) Info(msg string, keysAndValues ...any) {
+	l.log(slog.LevelInfo, msg, keysAndValues)
+}
+
+// Warn implements port.Logger.
+func (l *SlogLogger) Warn(msg string, keysAndValues ...any) {
+	l.log(slog.LevelWarn, msg, keysAndValues)
+}
+
+// Error implements port.Logger.
+func (l *SlogLogger) Error(msg string, keysAndValues ...any) {
+	l.log(slog.LevelError, msg, keysAndValues)
+}
+
+// With implements port.Logger.
+func (l *SlogLogger) With(keysAndValues ...any) port.Logger {
+	return &SlogLogger{logger: l.logger.With(keysAndValues...), ctx: l.ctx}
+}
+
+// WithContext implements port.Logger. The context is passed to the handler
+// of every entry; its context fields are added as attributes unless the
+// handler is backed by a logger.Logger, which extracts them itself.
+func (l *SlogLogger) WithContext(ctx context.Context) port.Logger {
+	sl := l.logger
+	if !logger.IsSlogHandler(sl.Handler()) {
+		sl = sl.With(logger.ContextFields(ctx)...)
+	}
+	return &SlogLogger{logger: sl, ctx: ctx}
+}
+
+// log writes an entry with the caller of the port.Logger method.
+func (l *SlogLogger) log(level slog.Level, msg string, keysAndValues []any) {
+	if !l.logger.Enabled(l.ctx, level) {
+		return
+	}
+
+	// Skip runtime.Callers, log and the port.Logger method
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+
+	record := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	record.Add(keysAndValues...)
+	_ = l.logger.Handler().Handle(l.ctx, record)
+}
@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/hapkiduki/order-go/pkg/ctxkeys"
+	"github.com/hapkiduki/order-go/pkg/logger/loggertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogLogger_WithContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxkeys.RequestID, "req-1")
+
+	t.Run("any handler gets the context fields as attributes", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+		log.WithContext(ctx).Info("order created", "order_id", "o-1")
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, "order created", entry["msg"])
+		assert.Equal(t, "req-1", entry["request_id"])
+		assert.Equal(t, "o-1", entry["order_id"])
+	})
+
+	t.Run("a logger.Logger handler extracts them itself", func(t *testing.T) {
+		rec := loggertest.New()
+		log := NewSlogLogger(rec.Logger().Slog())
+
+		log.WithContext(ctx).Info("order created", "order_id", "o-1")
+
+		entry := rec.RequireEntry(t, "info", "order created")
+		assert.Equal(t, map[string]any{"request_id": "req-1"}, entry.Context)
+		assert.Equal(t, map[string]any{"order_id": "o-1"}, entry.Fields)
+	})
+}
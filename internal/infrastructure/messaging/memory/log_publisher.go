@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+)
+
+// LogPublisher is a port.EventPublisher that logs every message at debug level
+// and drops it. Unlike Publisher it keeps nothing in memory, so it can stand in
+// for the broker in long-running processes.
+type LogPublisher struct {
+	logger port.Logger
+}
+
+// Compile-time check that LogPublisher implements port.EventPublisher.
+var _ port.EventPublisher = (*LogPublisher)(nil)
+
+// NewLogPublisher creates a publisher that only logs messages.
+//
+// Parameters:
+//   - logger: The logger messages are written to
+//
+// Returns:
+//   - *LogPublisher: The publisher
+func NewLogPublisher(logger port.Logger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+// Publish implements port.EventPublisher.
+func (p *LogPublisher) Publish(ctx context.Context, msg port.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	p.logger.Debug("Event dropped, no broker configured",
+		"message_id", msg.ID,
+		"event_type", msg.Type,
+		"aggregate_id", msg.AggregateID,
+	)
+	return nil
+}
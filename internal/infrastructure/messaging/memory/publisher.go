@@ -0,0 +1,104 @@
+// Package memory provides in-process implementations of port.EventPublisher:
+// messages never leave the process. Publisher records every message and is
+// intended for tests; LogPublisher only logs and drops them.
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+)
+
+// Handler processes a published message in-process.
+type Handler func(ctx context.Context, msg port.Message) error
+
+// Publisher is an in-memory implementation of port.EventPublisher.
+// It records every published message and forwards it to the subscribed handlers.
+// The recorded messages are never dropped (see Reset), so do not use it in
+// long-running processes.
+type Publisher struct {
+	mu       sync.RWMutex
+	messages []port.Message
+	handlers []Handler
+	err      error
+}
+
+// Compile-time check that Publisher implements port.EventPublisher.
+var _ port.EventPublisher = (*Publisher)(nil)
+
+// NewPublisher creates an empty in-memory publisher.
+//
+// Returns:
+//   - *Publisher: The publisher
+func NewPublisher() *Publisher {
+	return &Publisher{}
+}
+
+// Publish implements port.EventPublisher.
+// A handler error fails the publish, so the outbox relay retries the message.
+func (p *Publisher) Publish(ctx context.Context, msg port.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	p.mu.RLock()
+	err := p.err
+	handlers := p.handlers
+	p.mu.RUnlock()
+
+	if err != nil {
+		return err
+	}
+	for _, handler := range handlers {
+		if err := handler(ctx, msg); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	p.messages = append(p.messages, msg)
+	p.mu.Unlock()
+	return nil
+}
+
+// Subscribe registers a handler called for every published message.
+//
+// Parameters:
+//   - handler: The handler to call
+func (p *Publisher) Subscribe(handler Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers = append(p.handlers, handler)
+}
+
+// FailWith makes every subsequent Publish return err, simulating a broker outage.
+// Pass nil to recover.
+//
+// Parameters:
+//   - err: The error to return, or nil
+func (p *Publisher) FailWith(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+// Messages returns a copy of the messages published so far, in publishing order.
+//
+// Returns:
+//   - []port.Message: The published messages
+func (p *Publisher) Messages() []port.Message {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	out := make([]port.Message, len(p.messages))
+	copy(out, p.messages)
+	return out
+}
+
+// Reset discards the recorded messages.
+func (p *Publisher) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages = nil
+}
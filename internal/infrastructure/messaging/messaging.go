@@ -0,0 +1,86 @@
+// Package messaging delivers domain events to other services using the
+// transactional outbox pattern.
+//
+// Repositories store the events recorded by an aggregate in an outbox, in the
+// same transaction as the aggregate itself. The Relay then publishes pending
+// outbox messages through a port.EventPublisher, retrying with exponential
+// backoff. Delivery is at-least-once: consumers deduplicate by message ID.
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/domain/order"
+)
+
+// OrderEventPayload is the JSON body of order events.
+// Field names are part of the integration contract: never rename them.
+type OrderEventPayload struct {
+	EventID        string    `json:"event_id"`
+	EventType      string    `json:"event_type"`
+	OrderID        string    `json:"order_id"`
+	CustomerID     string    `json:"customer_id"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previous_status,omitempty"`
+	Total          int64     `json:"total"`
+	Currency       string    `json:"currency"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// NewOrderMessage serializes an order domain event into a message.
+//
+// Parameters:
+//   - e: The domain event
+//
+// Returns:
+//   - port.Message: The message ready to be stored in the outbox
+//   - error: Any encoding error
+func NewOrderMessage(e order.Event) (port.Message, error) {
+	payload, err := json.Marshal(OrderEventPayload{
+		EventID:        e.ID,
+		EventType:      string(e.Type),
+		OrderID:        e.OrderID,
+		CustomerID:     e.CustomerID,
+		Status:         string(e.Status),
+		PreviousStatus: string(e.PreviousStatus),
+		Total:          e.Total,
+		Currency:       e.Currency,
+		OccurredAt:     e.OccurredAt,
+	})
+	if err != nil {
+		return port.Message{}, fmt.Errorf("encode %s event: %w", e.Type, err)
+	}
+
+	return port.Message{
+		ID:            e.ID,
+		Type:          string(e.Type),
+		AggregateType: order.AggregateType,
+		AggregateID:   e.OrderID,
+		Payload:       payload,
+		OccurredAt:    e.OccurredAt,
+	}, nil
+}
+
+// NewOrderMessages serializes all events recorded by an order.
+//
+// Parameters:
+//   - o: The order whose pending events are serialized
+//
+// Returns:
+//   - []port.Message: The messages, in the order the events were recorded
+//   - error: Any encoding error
+func NewOrderMessages(o *order.Order) ([]port.Message, error) {
+	events := o.Events()
+	messages := make([]port.Message, 0, len(events))
+	for _, e := range events {
+		msg, err := NewOrderMessage(e)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
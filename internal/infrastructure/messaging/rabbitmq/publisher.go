@@ -0,0 +1,187 @@
+// Package rabbitmq provides a RabbitMQ implementation of port.EventPublisher
+// using the AMQP 0-9-1 client.
+//
+// Messages are published as persistent messages to a durable topic exchange,
+// with the event type as routing key, and publisher confirms enabled: Publish
+// only succeeds once the broker has taken responsibility for the message.
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/infrastructure/config"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrNotConfirmed is returned when the broker negatively acknowledges a message.
+var ErrNotConfirmed = errors.New("rabbitmq: message not confirmed by broker")
+
+// Publisher is a RabbitMQ implementation of port.EventPublisher.
+// The connection is re-established lazily after a broker or network failure.
+// Publishes are serialized over a single confirm-mode channel.
+type Publisher struct {
+	url            string
+	exchange       string
+	publishTimeout time.Duration
+
+	mu      sync.Mutex
+	conn    atomic.Pointer[amqp.Connection] // read without mu by the health checker
+	channel *amqp.Channel
+}
+
+// Compile-time check that Publisher implements port.EventPublisher.
+var _ port.EventPublisher = (*Publisher)(nil)
+
+// NewPublisher connects to RabbitMQ and declares the events exchange.
+//
+// Parameters:
+//   - cfg: RabbitMQ configuration
+//
+// Returns:
+//   - *Publisher: The publisher (must be closed)
+//   - error: Any error connecting or declaring the exchange
+func NewPublisher(cfg config.RabbitMQConfig) (*Publisher, error) {
+	p := &Publisher{
+		url:            cfg.URL.Value(),
+		exchange:       cfg.Exchange,
+		publishTimeout: cfg.PublishTimeout,
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.connect(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// connect opens a connection and a confirm-mode channel and declares the exchange.
+// Must be called with p.mu held.
+func (p *Publisher) connect() error {
+	conn, err := amqp.Dial(p.url)
+	if err != nil {
+		return fmt.Errorf("failed to connect to rabbitmq: %w", err)
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to open rabbitmq channel: %w", err)
+	}
+
+	if err := channel.ExchangeDeclare(p.exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to declare exchange %q: %w", p.exchange, err)
+	}
+
+	if err := channel.Confirm(false); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	p.conn.Store(conn)
+	p.channel = channel
+	return nil
+}
+
+// Publish implements port.EventPublisher.
+func (p *Publisher) Publish(ctx context.Context, msg port.Message) error {
+	if p.publishTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.publishTimeout)
+		defer cancel()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.channel == nil || p.channel.IsClosed() {
+		if conn := p.conn.Load(); conn != nil {
+			conn.Close()
+		}
+		if err := p.connect(); err != nil {
+			return err
+		}
+	}
+
+	confirmation, err := p.channel.PublishWithDeferredConfirmWithContext(ctx, p.exchange, msg.Type, false, false,
+		amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			MessageId:    msg.ID,
+			Type:         msg.Type,
+			Timestamp:    msg.OccurredAt,
+			Headers: amqp.Table{
+				"aggregate_type": msg.AggregateType,
+				"aggregate_id":   msg.AggregateID,
+			},
+			Body: msg.Payload,
+		})
+	if err != nil {
+		return fmt.Errorf("publish %s: %w", msg.Type, err)
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("publish %s: %w", msg.Type, err)
+	}
+	if !acked {
+		return fmt.Errorf("publish %s: %w", msg.Type, ErrNotConfirmed)
+	}
+	return nil
+}
+
+// Close closes the channel and the connection.
+//
+// Returns:
+//   - error: Any error closing the connection
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	conn := p.conn.Load()
+	if conn == nil || conn.IsClosed() {
+		return nil
+	}
+	return conn.Close()
+}
+
+// HealthChecker checks the RabbitMQ connection. It implements port.HealthChecker.
+type HealthChecker struct {
+	publisher *Publisher
+}
+
+// Compile-time check that HealthChecker implements port.HealthChecker.
+var _ port.HealthChecker = (*HealthChecker)(nil)
+
+// NewHealthChecker creates a RabbitMQ health checker.
+//
+// Parameters:
+//   - publisher: The publisher whose connection is checked
+//
+// Returns:
+//   - *HealthChecker: The health checker
+func NewHealthChecker(publisher *Publisher) *HealthChecker {
+	return &HealthChecker{publisher: publisher}
+}
+
+// Name implements port.HealthChecker.
+func (h *HealthChecker) Name() string {
+	return "rabbitmq"
+}
+
+// Check implements port.HealthChecker.
+// A closed connection is reported as degraded: events stay in the outbox and
+// are published once the broker is reachable again.
+func (h *HealthChecker) Check(ctx context.Context) error {
+	if conn := h.publisher.conn.Load(); conn == nil || conn.IsClosed() {
+		return fmt.Errorf("%w: rabbitmq connection is closed", port.ErrDegraded)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package messaging
+
+import (
+	"context"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+)
+
+// Metric names recorded by the relay.
+const (
+	// MetricOutboxPublishedTotal counts messages published by the relay, by event type.
+	MetricOutboxPublishedTotal = "outbox_published_total"
+
+	// MetricOutboxPublishFailuresTotal counts failed publish attempts, by event type.
+	MetricOutboxPublishFailuresTotal = "outbox_publish_failures_total"
+)
+
+// RelayConfig contains outbox relay configuration.
+type RelayConfig struct {
+	// PollInterval is how often the outbox is polled when idle
+	// Default: 1 second
+	PollInterval time.Duration
+
+	// BatchSize is the maximum number of messages claimed per poll
+	// Default: 100
+	BatchSize int
+
+	// Lease is how long claimed messages are hidden from other relays.
+	// It must be longer than the time needed to publish a batch.
+	// Default: 30 seconds
+	Lease time.Duration
+
+	// MinBackoff is the delay before the first retry of a failed message
+	// Default: 1 second
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the exponential retry delay
+	// Default: 5 minutes
+	MaxBackoff time.Duration
+
+	// Metrics records published and failed messages (optional, may be nil)
+	Metrics port.Metrics
+}
+
+// Relay publishes pending outbox messages.
+//
+// Ordering: the store only hands out the oldest pending message of each
+// aggregate, so a failing message holds back the later events of its aggregate
+// (and only of its aggregate) until it is published. Failed messages are retried
+// forever with exponential backoff capped at MaxBackoff.
+type Relay struct {
+	store     port.OutboxStore
+	publisher port.EventPublisher
+	config    RelayConfig
+	logger    port.Logger
+}
+
+// NewRelay creates an outbox relay.
+//
+// Parameters:
+//   - store: The outbox the messages are read from
+//   - publisher: The publisher messages are delivered to
+//   - config: Relay configuration
+//   - logger: The logger to use
+//
+// Returns:
+//   - *Relay: The relay (call Run to start it)
+func NewRelay(store port.OutboxStore, publisher port.EventPublisher, config RelayConfig, logger port.Logger) *Relay {
+	// Set defaults if not provided
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.Lease <= 0 {
+		config.Lease = 30 * time.Second
+	}
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = time.Second
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = 5 * time.Minute
+	}
+	config.MaxBackoff = max(config.MaxBackoff, config.MinBackoff)
+
+	return &Relay{
+		store:     store,
+		publisher: publisher,
+		config:    config,
+		logger:    logger,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled.
+// While messages are being published it keeps draining without waiting,
+// so a backlog is cleared as fast as the broker accepts it.
+//
+// Parameters:
+//   - ctx: Context controlling the relay lifetime
+func (r *Relay) Run(ctx context.Context) {
+	r.logger.Info("Outbox relay started",
+		"poll_interval", r.config.PollInterval.String(),
+		"batch_size", r.config.BatchSize,
+	)
+
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			published, err := r.RelayOnce(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					r.logger.Error("Failed to read outbox", "error", err)
+				}
+				break
+			}
+			if published == 0 {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce claims one batch of pending messages and publishes it.
+//
+// Parameters:
+//   - ctx: The context for the store and publisher calls
+//
+// Returns:
+//   - int: The number of messages published
+//   - error: An error claiming messages; publish failures are retried, not returned
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	messages, err := r.store.ClaimPending(ctx, r.config.BatchSize, r.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, msg := range messages {
+		if ctx.Err() != nil {
+			// Unpublished claims become visible again once their lease expires
+			break
+		}
+		if r.publish(ctx, msg) {
+			published++
+		}
+	}
+	return published, nil
+}
+
+// publish delivers a single message and records the outcome in the store.
+func (r *Relay) publish(ctx context.Context, msg port.OutboxMessage) bool {
+	// Outcomes are recorded even if the relay is shutting down
+	storeCtx := context.WithoutCancel(ctx)
+	tags := map[string]string{"event_type": msg.Type}
+	log := r.logger.With(
+		"message_id", msg.ID,
+		"event_type", msg.Type,
+		"aggregate_id", msg.AggregateID,
+	)
+
+	if err := r.publisher.Publish(ctx, msg.Message); err != nil {
+		attempts := msg.Attempts + 1
+		next := time.Now().Add(r.backoff(attempts))
+		log.Warn("Failed to publish event, will retry",
+			"error", err,
+			"attempts", attempts,
+			"next_attempt_at", next,
+		)
+		if r.config.Metrics != nil {
+			r.config.Metrics.Counter(MetricOutboxPublishFailuresTotal, 1, tags)
+		}
+		if err := r.store.MarkFailed(storeCtx, msg.ID, next, err); err != nil {
+			log.Error("Failed to record publish failure", "error", err)
+		}
+		return false
+	}
+
+	if err := r.store.MarkPublished(storeCtx, msg.ID); err != nil {
+		// The message will be published again once its lease expires
+		log.Error("Failed to mark event as published", "error", err)
+		return false
+	}
+
+	if r.config.Metrics != nil {
+		r.config.Metrics.Counter(MetricOutboxPublishedTotal, 1, tags)
+	}
+	log.Debug("Event published")
+	return true
+}
+
+// backoff returns the delay before the given retry attempt: MinBackoff doubled
+// for every previous failure, capped at MaxBackoff.
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.config.MinBackoff
+	for i := 1; i < attempts && delay < r.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, r.config.MaxBackoff)
+}
@@ -0,0 +1,220 @@
+package messaging_test
+
+// The relay is tested against the in-memory outbox, which imports this
+// package, hence the external test package.
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/domain/order"
+	"github.com/hapkiduki/order-go/internal/infrastructure/logging"
+	"github.com/hapkiduki/order-go/internal/infrastructure/messaging"
+	messagingmemory "github.com/hapkiduki/order-go/internal/infrastructure/messaging/memory"
+	"github.com/hapkiduki/order-go/internal/infrastructure/persistence/memory"
+	"github.com/hapkiduki/order-go/pkg/logger/loggertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBackoff is short enough for tests to wait out a retry.
+const testBackoff = 50 * time.Millisecond
+
+type relayFixture struct {
+	outbox    *memory.Outbox
+	repo      *memory.OrderRepository
+	publisher *messagingmemory.Publisher
+	logs      *loggertest.Recorder
+	relay     *messaging.Relay
+}
+
+func newRelayFixture(t *testing.T, store func(*memory.Outbox) port.OutboxStore) *relayFixture {
+	t.Helper()
+
+	f := &relayFixture{
+		outbox:    memory.NewOutbox(),
+		publisher: messagingmemory.NewPublisher(),
+		logs:      loggertest.New(),
+	}
+	f.repo = memory.NewOrderRepository(f.outbox)
+
+	var s port.OutboxStore = f.outbox
+	if store != nil {
+		s = store(f.outbox)
+	}
+	f.relay = messaging.NewRelay(s, f.publisher, messaging.RelayConfig{
+		MinBackoff: testBackoff,
+		MaxBackoff: testBackoff,
+	}, logging.New(f.logs.Logger()))
+	return f
+}
+
+// placeOrder saves a new order moved through the given statuses, one save per
+// step, and returns its ID.
+func (f *relayFixture) placeOrder(t *testing.T, path ...order.Status) string {
+	t.Helper()
+
+	ctx := context.Background()
+	o, err := order.New("customer-1", "USD", []order.LineItem{
+		{ProductID: "sku-1", Name: "Widget", Quantity: 1, UnitPrice: 1500},
+	})
+	require.NoError(t, err)
+	require.NoError(t, f.repo.Save(ctx, o))
+	for _, status := range path {
+		require.NoError(t, o.TransitionTo(status))
+		require.NoError(t, f.repo.Save(ctx, o))
+	}
+	return o.ID()
+}
+
+// relayAll runs the relay until a pass publishes nothing and returns the total.
+func (f *relayFixture) relayAll(t *testing.T) int {
+	t.Helper()
+
+	total := 0
+	for {
+		published, err := f.relay.RelayOnce(context.Background())
+		require.NoError(t, err)
+		if published == 0 {
+			return total
+		}
+		total += published
+	}
+}
+
+// publishedTypes returns the types of the published events of an aggregate, in
+// publishing order.
+func (f *relayFixture) publishedTypes(aggregateID string) []string {
+	var types []string
+	for _, msg := range f.publisher.Messages() {
+		if msg.AggregateID == aggregateID {
+			types = append(types, msg.Type)
+		}
+	}
+	return types
+}
+
+func TestRelay_PublishesInOrderPerAggregate(t *testing.T) {
+	f := newRelayFixture(t, nil)
+	first := f.placeOrder(t, order.StatusConfirmed, order.StatusPaid, order.StatusShipped)
+	second := f.placeOrder(t, order.StatusCancelled)
+
+	// One message per aggregate per pass
+	published, err := f.relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+
+	assert.Equal(t, 4, f.relayAll(t))
+	assert.Equal(t, []string{
+		string(order.EventOrderCreated),
+		string(order.EventOrderConfirmed),
+		string(order.EventOrderPaid),
+		string(order.EventOrderShipped),
+	}, f.publishedTypes(first))
+	assert.Equal(t, []string{
+		string(order.EventOrderCreated),
+		string(order.EventOrderCancelled),
+	}, f.publishedTypes(second))
+}
+
+func TestRelay_MarksPublished(t *testing.T) {
+	f := newRelayFixture(t, nil)
+	f.placeOrder(t, order.StatusConfirmed)
+	require.Equal(t, 2, f.outbox.Pending())
+
+	assert.Equal(t, 2, f.relayAll(t))
+
+	assert.Zero(t, f.outbox.Pending(), "published messages leave the outbox")
+	assert.Zero(t, f.relayAll(t), "nothing is published twice")
+	assert.Len(t, f.publisher.Messages(), 2)
+}
+
+func TestRelay_RetriesFailedPublish(t *testing.T) {
+	f := newRelayFixture(t, nil)
+	failing := f.placeOrder(t, order.StatusConfirmed)
+	healthy := f.placeOrder(t)
+
+	// The broker rejects the events of one aggregate
+	outage := errors.New("broker unavailable")
+	var recovered atomic.Bool
+	f.publisher.Subscribe(func(_ context.Context, msg port.Message) error {
+		if msg.AggregateID == failing && !recovered.Load() {
+			return outage
+		}
+		return nil
+	})
+
+	assert.Equal(t, 1, f.relayAll(t))
+	assert.Equal(t, []string{string(order.EventOrderCreated)}, f.publishedTypes(healthy), "other aggregates are not held back")
+	assert.Empty(t, f.publishedTypes(failing))
+	assert.Equal(t, 2, f.outbox.Pending(), "the failed message and the one behind it are kept")
+
+	entry := f.logs.RequireEntry(t, "warn", "Failed to publish event, will retry")
+	entry.AssertField(t, "aggregate_id", failing)
+	entry.AssertField(t, "attempts", 1)
+	entry.AssertField(t, "error", outage)
+
+	// Not retried before the backoff is over
+	recovered.Store(true)
+	assert.Zero(t, f.relayAll(t))
+
+	// Then the aggregate's events follow in order
+	assert.Eventually(t, func() bool {
+		published, err := f.relay.RelayOnce(context.Background())
+		return err == nil && published > 0
+	}, time.Second, testBackoff/5)
+	f.relayAll(t)
+
+	assert.Equal(t, []string{
+		string(order.EventOrderCreated),
+		string(order.EventOrderConfirmed),
+	}, f.publishedTypes(failing))
+	assert.Zero(t, f.outbox.Pending())
+}
+
+// failingMarkStore is an outbox that cannot record published messages.
+type failingMarkStore struct {
+	*memory.Outbox
+}
+
+func (s failingMarkStore) MarkPublished(context.Context, string) error {
+	return errors.New("connection reset")
+}
+
+func TestRelay_MarkPublishedFails(t *testing.T) {
+	f := newRelayFixture(t, func(o *memory.Outbox) port.OutboxStore { return failingMarkStore{o} })
+	f.placeOrder(t)
+
+	published, err := f.relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Zero(t, published)
+	assert.Len(t, f.publisher.Messages(), 1, "delivered")
+	assert.Equal(t, 1, f.outbox.Pending(), "published again once the lease expires")
+	f.logs.RequireEntry(t, "error", "Failed to mark event as published")
+}
+
+func TestRelay_RunStopsOnCancel(t *testing.T) {
+	f := newRelayFixture(t, nil)
+	f.placeOrder(t, order.StatusConfirmed)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.relay.Run(ctx)
+	}()
+
+	assert.Eventually(t, func() bool { return f.outbox.Pending() == 0 }, time.Second, time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("relay did not stop")
+	}
+	f.logs.RequireEntry(t, "info", "Outbox relay stopped")
+}
@@ -0,0 +1,192 @@
+// Package metrics provides implementations of the port.Metrics interface.
+//
+// The Prometheus adapter registers collectors lazily: the first call for a
+// metric name defines its label set (the sorted tag keys). Later calls with a
+// different label set for the same name are dropped rather than panicking.
+package metrics
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// collector is a registered metric vector with its label names.
+type collector struct {
+	labels []string
+	vec    any // *prometheus.CounterVec, *prometheus.GaugeVec or *prometheus.HistogramVec
+}
+
+// Prometheus is a Prometheus-backed implementation of port.Metrics.
+// It is safe for concurrent use.
+type Prometheus struct {
+	namespace string
+	buckets   []float64
+	registry  *prometheus.Registry
+
+	mu         sync.RWMutex
+	collectors map[string]*collector
+}
+
+// Compile-time check that Prometheus implements port.Metrics.
+var _ port.Metrics = (*Prometheus)(nil)
+
+// NewPrometheus creates a Prometheus metrics adapter with its own registry,
+// including the Go runtime and process collectors.
+//
+// Parameters:
+//   - namespace: Prefix for all metric names (e.g., "order_go")
+//
+// Returns:
+//   - *Prometheus: The metrics adapter
+func NewPrometheus(namespace string) *Prometheus {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return &Prometheus{
+		namespace:  namespace,
+		buckets:    prometheus.DefBuckets,
+		registry:   registry,
+		collectors: make(map[string]*collector),
+	}
+}
+
+// Handler returns the HTTP handler that exposes the metrics in the Prometheus text format.
+//
+// Returns:
+//   - http.Handler: The /metrics handler
+func (p *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{})
+}
+
+// Registry returns the underlying Prometheus registry.
+// Use this to register custom collectors.
+//
+// Returns:
+//   - *prometheus.Registry: The registry
+func (p *Prometheus) Registry() *prometheus.Registry {
+	return p.registry
+}
+
+// Counter implements port.Metrics.
+func (p *Prometheus) Counter(name string, value float64, tags map[string]string) {
+	labels, values := splitTags(tags)
+	vec, ok := p.get(name, labels, func() (prometheus.Collector, any) {
+		v := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: p.namespace,
+			Name:      name,
+			Help:      name,
+		}, labels)
+		return v, v
+	}).(*prometheus.CounterVec)
+	if ok {
+		vec.WithLabelValues(values...).Add(value)
+	}
+}
+
+// Gauge implements port.Metrics.
+func (p *Prometheus) Gauge(name string, value float64, tags map[string]string) {
+	if gauge := p.gauge(name, tags); gauge != nil {
+		gauge.Set(value)
+	}
+}
+
+// GaugeAdd implements port.Metrics.
+func (p *Prometheus) GaugeAdd(name string, delta float64, tags map[string]string) {
+	if gauge := p.gauge(name, tags); gauge != nil {
+		gauge.Add(delta)
+	}
+}
+
+// gauge returns the gauge for name and tags, or nil if name is registered
+// with other labels or as another type.
+func (p *Prometheus) gauge(name string, tags map[string]string) prometheus.Gauge {
+	labels, values := splitTags(tags)
+	vec, ok := p.get(name, labels, func() (prometheus.Collector, any) {
+		v := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: p.namespace,
+			Name:      name,
+			Help:      name,
+		}, labels)
+		return v, v
+	}).(*prometheus.GaugeVec)
+	if !ok {
+		return nil
+	}
+	return vec.WithLabelValues(values...)
+}
+
+// Histogram implements port.Metrics.
+func (p *Prometheus) Histogram(name string, value float64, tags map[string]string) {
+	labels, values := splitTags(tags)
+	vec, ok := p.get(name, labels, func() (prometheus.Collector, any) {
+		v := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: p.namespace,
+			Name:      name,
+			Help:      name,
+			Buckets:   p.buckets,
+		}, labels)
+		return v, v
+	}).(*prometheus.HistogramVec)
+	if ok {
+		vec.WithLabelValues(values...).Observe(value)
+	}
+}
+
+// Timing implements port.Metrics. Durations are recorded in seconds,
+// so name should end in "_seconds" by Prometheus convention.
+func (p *Prometheus) Timing(name string, duration time.Duration, tags map[string]string) {
+	p.Histogram(name, duration.Seconds(), tags)
+}
+
+// get returns the collector for name, registering it on first use.
+// It returns nil if name is already registered with different labels or as another type.
+func (p *Prometheus) get(name string, labels []string, create func() (prometheus.Collector, any)) any {
+	p.mu.RLock()
+	c, ok := p.collectors[name]
+	p.mu.RUnlock()
+
+	if !ok {
+		p.mu.Lock()
+		if c, ok = p.collectors[name]; !ok {
+			col, vec := create()
+			if err := p.registry.Register(col); err != nil {
+				p.mu.Unlock()
+				return nil
+			}
+			c = &collector{labels: labels, vec: vec}
+			p.collectors[name] = c
+		}
+		p.mu.Unlock()
+	}
+
+	if !slices.Equal(c.labels, labels) {
+		return nil
+	}
+	return c.vec
+}
+
+// splitTags returns the sorted tag keys and their values in the same order.
+func splitTags(tags map[string]string) ([]string, []string) {
+	labels := make([]string, 0, len(tags))
+	for k := range tags {
+		labels = append(labels, k)
+	}
+	sort.Strings(labels)
+
+	values := make([]string, len(labels))
+	for i, k := range labels {
+		values[i] = tags[k]
+	}
+	return labels, values
+}
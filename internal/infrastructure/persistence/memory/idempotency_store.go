@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+)
+
+// idempotencySweepInterval is how often expired keys are removed.
+const idempotencySweepInterval = time.Minute
+
+// idempotencyEntry stores a record with its expiration time.
+type idempotencyEntry struct {
+	record    port.IdempotencyRecord
+	expiresAt time.Time
+}
+
+// IdempotencyStore is an in-memory implementation of port.IdempotencyStore.
+// It only protects a single process; use the Redis store when running several replicas.
+type IdempotencyStore struct {
+	mu        sync.Mutex
+	entries   map[string]*idempotencyEntry
+	lastSweep time.Time
+}
+
+// Compile-time check that IdempotencyStore implements port.IdempotencyStore.
+var _ port.IdempotencyStore = (*IdempotencyStore)(nil)
+
+// NewIdempotencyStore creates an empty in-memory idempotency store.
+//
+// Returns:
+//   - *IdempotencyStore: The store
+func NewIdempotencyStore() *IdempotencyStore {
+	return &IdempotencyStore{
+		entries:   make(map[string]*idempotencyEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// Reserve implements port.IdempotencyStore.
+func (s *IdempotencyStore) Reserve(
+	ctx context.Context, key, fingerprint string, lockTTL time.Duration,
+) (*port.IdempotencyRecord, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	if entry, ok := s.entries[key]; ok && now.Before(entry.expiresAt) {
+		record := entry.record
+		return &record, false, nil
+	}
+
+	s.entries[key] = &idempotencyEntry{
+		record: port.IdempotencyRecord{
+			Fingerprint: fingerprint,
+			CreatedAt:   now.UTC(),
+		},
+		expiresAt: now.Add(lockTTL),
+	}
+	return nil, true, nil
+}
+
+// Complete implements port.IdempotencyStore.
+func (s *IdempotencyStore) Complete(
+	ctx context.Context, key string, record port.IdempotencyRecord, ttl time.Duration,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	record.Completed = true
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = &idempotencyEntry{
+		record:    record,
+		expiresAt: time.Now().Add(ttl),
+	}
+	return nil
+}
+
+// Release implements port.IdempotencyStore.
+func (s *IdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[key]; ok && !entry.record.Completed {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// sweep removes expired entries at most once per idempotencySweepInterval.
+// Must be called with s.mu held.
+func (s *IdempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < idempotencySweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
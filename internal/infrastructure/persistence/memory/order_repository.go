@@ -0,0 +1,132 @@
+// Package memory provides in-memory implementations of the persistence ports.
+// They are intended for local development and tests; data is lost on restart.
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/domain/order"
+	"github.com/hapkiduki/order-go/internal/infrastructure/messaging"
+)
+
+// OrderRepository is an in-memory implementation of port.OrderRepository.
+// Orders are stored as snapshots so callers never share state with the store.
+// Recorded domain events are appended to the outbox together with the order.
+type OrderRepository struct {
+	mu     sync.RWMutex
+	orders map[string]order.Snapshot
+	outbox *Outbox
+}
+
+// Compile-time check that OrderRepository implements port.OrderRepository.
+var _ port.OrderRepository = (*OrderRepository)(nil)
+
+// NewOrderRepository creates an empty in-memory order repository.
+//
+// Parameters:
+//   - outbox: The outbox domain events are written to (nil discards events)
+//
+// Returns:
+//   - *OrderRepository: The repository
+func NewOrderRepository(outbox *Outbox) *OrderRepository {
+	return &OrderRepository{
+		orders: make(map[string]order.Snapshot),
+		outbox: outbox,
+	}
+}
+
+// Save implements port.OrderRepository.
+func (r *OrderRepository) Save(ctx context.Context, o *order.Order) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	messages, err := messaging.NewOrderMessages(o)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Optimistic locking: the stored version must be the one o was loaded at
+	// (a missing order has version 0)
+	if stored := r.orders[o.ID()]; stored.Version != o.Version() {
+		return order.ErrConcurrentModification
+	}
+
+	snapshot := o.Snapshot()
+	snapshot.Version++
+	r.orders[o.ID()] = snapshot
+	if r.outbox != nil {
+		r.outbox.append(messages)
+	}
+	o.SetVersion(snapshot.Version)
+	o.ClearEvents()
+	return nil
+}
+
+// FindByID implements port.OrderRepository.
+func (r *OrderRepository) FindByID(ctx context.Context, id string) (*order.Order, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	snapshot, ok := r.orders[id]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, order.ErrNotFound
+	}
+	return order.Restore(snapshot)
+}
+
+// List implements port.OrderRepository.
+func (r *OrderRepository) List(ctx context.Context, filter port.OrderFilter) ([]*order.Order, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	r.mu.RLock()
+	matches := make([]order.Snapshot, 0, len(r.orders))
+	for _, s := range r.orders {
+		if filter.CustomerID != "" && s.CustomerID != filter.CustomerID {
+			continue
+		}
+		if filter.Status != "" && s.Status != filter.Status {
+			continue
+		}
+		matches = append(matches, s)
+	}
+	r.mu.RUnlock()
+
+	// Newest first, ID as tie-breaker for a stable order
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].CreatedAt.Equal(matches[j].CreatedAt) {
+			return matches[i].ID < matches[j].ID
+		}
+		return matches[i].CreatedAt.After(matches[j].CreatedAt)
+	})
+
+	total := len(matches)
+	start := min(filter.Offset, total)
+	end := total
+	if filter.Limit > 0 {
+		end = min(start+filter.Limit, total)
+	}
+
+	result := make([]*order.Order, 0, end-start)
+	for _, s := range matches[start:end] {
+		o, err := order.Restore(s)
+		if err != nil {
+			return nil, 0, err
+		}
+		result = append(result, o)
+	}
+
+	return result, total, nil
+}
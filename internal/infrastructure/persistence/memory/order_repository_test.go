@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hapkiduki/order-go/internal/domain/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOrder(t *testing.T) *order.Order {
+	t.Helper()
+
+	o, err := order.New("customer-1", "USD", []order.LineItem{
+		{ProductID: "sku-1", Name: "Widget", Quantity: 2, UnitPrice: 1500},
+	})
+	require.NoError(t, err)
+	return o
+}
+
+func TestOrderRepository_SaveBumpsVersion(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository(nil)
+	o := newTestOrder(t)
+
+	require.NoError(t, repo.Save(ctx, o))
+	assert.Equal(t, 1, o.Version())
+
+	require.NoError(t, o.Confirm())
+	require.NoError(t, repo.Save(ctx, o))
+	assert.Equal(t, 2, o.Version())
+
+	stored, err := repo.FindByID(ctx, o.ID())
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored.Version())
+	assert.Equal(t, order.StatusConfirmed, stored.Status())
+}
+
+func TestOrderRepository_SaveRejectsStaleVersion(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository(nil)
+	o := newTestOrder(t)
+	require.NoError(t, repo.Save(ctx, o))
+
+	// Two requests load the same pending order: one cancels it, one confirms it
+	cancelled, err := repo.FindByID(ctx, o.ID())
+	require.NoError(t, err)
+	confirmed, err := repo.FindByID(ctx, o.ID())
+	require.NoError(t, err)
+
+	require.NoError(t, cancelled.Cancel())
+	require.NoError(t, repo.Save(ctx, cancelled))
+
+	require.NoError(t, confirmed.Confirm())
+	err = repo.Save(ctx, confirmed)
+	require.ErrorIs(t, err, order.ErrConcurrentModification)
+
+	stored, err := repo.FindByID(ctx, o.ID())
+	require.NoError(t, err)
+	assert.Equal(t, order.StatusCancelled, stored.Status())
+}
+
+func TestOrderRepository_StaleSaveWritesNoEvents(t *testing.T) {
+	ctx := context.Background()
+	outbox := NewOutbox()
+	repo := NewOrderRepository(outbox)
+	o := newTestOrder(t)
+	require.NoError(t, repo.Save(ctx, o))
+
+	stale, err := repo.FindByID(ctx, o.ID())
+	require.NoError(t, err)
+	current, err := repo.FindByID(ctx, o.ID())
+	require.NoError(t, err)
+
+	require.NoError(t, current.Cancel())
+	require.NoError(t, repo.Save(ctx, current))
+	require.NoError(t, stale.Confirm())
+	require.ErrorIs(t, repo.Save(ctx, stale), order.ErrConcurrentModification)
+
+	// order.created and order.cancelled only
+	messages, err := outbox.ClaimPending(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, string(order.EventOrderCreated), messages[0].Type)
+	require.NoError(t, outbox.MarkPublished(ctx, messages[0].ID))
+
+	messages, err = outbox.ClaimPending(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, string(order.EventOrderCancelled), messages[0].Type)
+}
+
+func TestOrderRepository_SaveRejectsDuplicateNewOrder(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository(nil)
+	o := newTestOrder(t)
+	require.NoError(t, repo.Save(ctx, o))
+
+	// A new (unsaved) order with a taken ID
+	duplicate, err := order.Restore(order.Snapshot{
+		ID:         o.ID(),
+		CustomerID: "customer-2",
+		Currency:   "USD",
+		Status:     order.StatusPending,
+	})
+	require.NoError(t, err)
+	require.ErrorIs(t, repo.Save(ctx, duplicate), order.ErrConcurrentModification)
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+)
+
+// outboxEntry is a pending message with its delivery state.
+type outboxEntry struct {
+	seq           int64
+	message       port.OutboxMessage
+	nextAttemptAt time.Time
+	lockedUntil   time.Time
+}
+
+// Outbox is an in-memory implementation of port.OutboxStore.
+// Published messages are removed, so only pending messages use memory.
+type Outbox struct {
+	mu      sync.Mutex
+	seq     int64
+	entries map[string]*outboxEntry
+}
+
+// Compile-time check that Outbox implements port.OutboxStore.
+var _ port.OutboxStore = (*Outbox)(nil)
+
+// NewOutbox creates an empty in-memory outbox.
+//
+// Returns:
+//   - *Outbox: The outbox
+func NewOutbox() *Outbox {
+	return &Outbox{
+		entries: make(map[string]*outboxEntry),
+	}
+}
+
+// append stores messages as pending, preserving their order.
+func (o *Outbox) append(messages []port.Message) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := time.Now()
+	for _, msg := range messages {
+		o.seq++
+		o.entries[msg.ID] = &outboxEntry{
+			seq:           o.seq,
+			message:       port.OutboxMessage{Message: msg},
+			nextAttemptAt: now,
+		}
+	}
+}
+
+// ClaimPending implements port.OutboxStore.
+func (o *Outbox) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]port.OutboxMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	// Find the oldest pending message of each aggregate
+	heads := make(map[string]*outboxEntry)
+	for _, entry := range o.entries {
+		head, ok := heads[entry.message.AggregateID]
+		if !ok || entry.seq < head.seq {
+			heads[entry.message.AggregateID] = entry
+		}
+	}
+
+	now := time.Now()
+	due := make([]*outboxEntry, 0, len(heads))
+	for _, entry := range heads {
+		if !entry.nextAttemptAt.After(now) && !entry.lockedUntil.After(now) {
+			due = append(due, entry)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].seq < due[j].seq })
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]port.OutboxMessage, 0, len(due))
+	for _, entry := range due {
+		entry.lockedUntil = now.Add(lease)
+		claimed = append(claimed, entry.message)
+	}
+	return claimed, nil
+}
+
+// MarkPublished implements port.OutboxStore.
+func (o *Outbox) MarkPublished(_ context.Context, id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.entries, id)
+	return nil
+}
+
+// MarkFailed implements port.OutboxStore.
+func (o *Outbox) MarkFailed(_ context.Context, id string, nextAttemptAt time.Time, _ error) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entry, ok := o.entries[id]
+	if !ok {
+		return nil
+	}
+	entry.message.Attempts++
+	entry.nextAttemptAt = nextAttemptAt
+	entry.lockedUntil = time.Time{}
+	return nil
+}
+
+// Pending returns the number of messages waiting to be published.
+//
+// Returns:
+//   - int: The number of pending messages
+func (o *Outbox) Pending() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
@@ -0,0 +1,155 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"golang.org/x/time/rate"
+)
+
+// limiterEntry stores a rate limiter with its last access time.
+// lastAccess is stored as unix timestamp (int64) for atomic operations.
+type limiterEntry struct {
+	limiter    *rate.Limiter
+	lastAccess int64 // unix timestamp in nanoseconds (atomic)
+}
+
+// RateLimitStore is an in-memory implementation of port.RateLimitStore using
+// one token bucket per key. Limits only hold within a single process; use the
+// Redis store when running several replicas.
+type RateLimitStore struct {
+	mu          sync.RWMutex
+	limiters    map[string]*limiterEntry
+	inactiveTTL time.Duration
+	cancel      context.CancelFunc
+}
+
+// Compile-time check that RateLimitStore implements port.RateLimitStore.
+var _ port.RateLimitStore = (*RateLimitStore)(nil)
+
+// NewRateLimitStore creates an in-memory rate limit store and starts the
+// goroutine that removes inactive buckets to prevent memory leaks.
+//
+// Parameters:
+//   - cleanupInterval: How often inactive buckets are removed (default: 5 minutes)
+//   - inactiveTTL: How long a bucket can be inactive before being removed (default: 10 minutes)
+//
+// Returns:
+//   - *RateLimitStore: The store (call Close to stop the cleanup goroutine)
+func NewRateLimitStore(cleanupInterval, inactiveTTL time.Duration) *RateLimitStore {
+	// Set defaults if not provided
+	if cleanupInterval <= 0 {
+		cleanupInterval = 5 * time.Minute
+	}
+	if inactiveTTL <= 0 {
+		inactiveTTL = 10 * time.Minute
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &RateLimitStore{
+		limiters:    make(map[string]*limiterEntry),
+		inactiveTTL: inactiveTTL,
+		cancel:      cancel,
+	}
+
+	go func() {
+		ticker := time.NewTicker(cleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.cleanup()
+			}
+		}
+	}()
+
+	return s
+}
+
+// Allow implements port.RateLimitStore.
+func (s *RateLimitStore) Allow(_ context.Context, key string, limit port.RateLimit) (port.RateLimitResult, error) {
+	now := time.Now()
+	limiter := s.get(key, limit, now.UnixNano())
+
+	// Apply limit changes (e.g., after a configuration reload)
+	if limiter.Limit() != rate.Limit(limit.Rate) {
+		limiter.SetLimitAt(now, rate.Limit(limit.Rate))
+	}
+	if limiter.Burst() != limit.Burst {
+		limiter.SetBurstAt(now, limit.Burst)
+	}
+
+	reservation := limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return port.RateLimitResult{Allowed: false, RetryAfter: time.Second}, nil
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		// Give the token back: the request is rejected, not delayed
+		reservation.CancelAt(now)
+		return port.RateLimitResult{Allowed: false, RetryAfter: delay}, nil
+	}
+	return port.RateLimitResult{Allowed: true}, nil
+}
+
+// Close stops the cleanup goroutine.
+func (s *RateLimitStore) Close() {
+	s.cancel()
+}
+
+// get returns the limiter for key, creating it if needed.
+func (s *RateLimitStore) get(key string, limit port.RateLimit, now int64) *rate.Limiter {
+	s.mu.RLock()
+	entry, exists := s.limiters[key]
+	if exists {
+		// Update last access time atomically while holding read lock
+		// This prevents race condition where entry could be deleted between
+		// releasing read lock and acquiring write lock.
+		atomic.StoreInt64(&entry.lastAccess, now)
+	}
+	s.mu.RUnlock()
+
+	if exists {
+		return entry.limiter
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Double-check after acquiring write lock
+	if entry, exists = s.limiters[key]; exists {
+		atomic.StoreInt64(&entry.lastAccess, now)
+		return entry.limiter
+	}
+
+	// Create new limiter
+	limiter := rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst)
+	s.limiters[key] = &limiterEntry{
+		limiter:    limiter,
+		lastAccess: now,
+	}
+
+	return limiter
+}
+
+// cleanup removes limiters that haven't been accessed within the inactive TTL.
+func (s *RateLimitStore) cleanup() {
+	now := time.Now().UnixNano()
+	cutoff := now - s.inactiveTTL.Nanoseconds()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, entry := range s.limiters {
+		// read lastAccess atomically
+		lastAccess := atomic.LoadInt64(&entry.lastAccess)
+		if lastAccess < cutoff {
+			delete(s.limiters, key)
+		}
+	}
+}
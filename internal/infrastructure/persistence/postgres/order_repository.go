@@ -0,0 +1,265 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/domain/order"
+	"github.com/hapkiduki/order-go/internal/infrastructure/messaging"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OrderRepository is a PostgreSQL implementation of port.OrderRepository.
+// Orders, their line items and the domain events they recorded
+// (into the outbox table) are written in a single transaction.
+type OrderRepository struct {
+	pool         *pgxpool.Pool
+	queryTimeout time.Duration
+}
+
+// Compile-time check that OrderRepository implements port.OrderRepository.
+var _ port.OrderRepository = (*OrderRepository)(nil)
+
+// NewOrderRepository creates a PostgreSQL order repository.
+//
+// Parameters:
+//   - pool: The connection pool
+//   - queryTimeout: Maximum duration of each repository call (0 disables the timeout)
+//
+// Returns:
+//   - *OrderRepository: The repository
+func NewOrderRepository(pool *pgxpool.Pool, queryTimeout time.Duration) *OrderRepository {
+	return &OrderRepository{
+		pool:         pool,
+		queryTimeout: queryTimeout,
+	}
+}
+
+// withTimeout derives a context bounded by the configured query timeout.
+func (r *OrderRepository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.queryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.queryTimeout)
+}
+
+// Save implements port.OrderRepository.
+// The order row is upserted and its line items are replaced. The upsert only
+// applies while the stored version is the one the order was loaded at; a new
+// order has version 0, so it never overwrites an existing row.
+func (r *OrderRepository) Save(ctx context.Context, o *order.Order) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	s := o.Snapshot()
+	messages, err := messaging.NewOrderMessages(o)
+	if err != nil {
+		return err
+	}
+
+	version := s.Version + 1
+	err = pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO orders (id, customer_id, currency, status, version, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (id) DO UPDATE SET
+				status     = EXCLUDED.status,
+				version    = EXCLUDED.version,
+				updated_at = EXCLUDED.updated_at
+			WHERE orders.version = $8`,
+			s.ID, s.CustomerID, s.Currency, string(s.Status), version, s.CreatedAt, s.UpdatedAt, s.Version,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to upsert order: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return order.ErrConcurrentModification
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM order_lines WHERE order_id = $1`, s.ID); err != nil {
+			return fmt.Errorf("failed to delete order lines: %w", err)
+		}
+
+		rows := make([][]any, 0, len(s.Lines))
+		for i, line := range s.Lines {
+			rows = append(rows, []any{s.ID, i + 1, line.ProductID, line.Name, line.Quantity, line.UnitPrice})
+		}
+		if _, err := tx.CopyFrom(ctx,
+			pgx.Identifier{"order_lines"},
+			[]string{"order_id", "line_no", "product_id", "name", "quantity", "unit_price"},
+			pgx.CopyFromRows(rows),
+		); err != nil {
+			return fmt.Errorf("failed to insert order lines: %w", err)
+		}
+
+		return insertOutboxMessages(ctx, tx, messages)
+	})
+	if err != nil {
+		return fmt.Errorf("save order %s: %w", s.ID, err)
+	}
+
+	o.SetVersion(version)
+	o.ClearEvents()
+	return nil
+}
+
+// FindByID implements port.OrderRepository.
+func (r *OrderRepository) FindByID(ctx context.Context, id string) (*order.Order, error) {
+	// Malformed IDs cannot exist; avoid a database round-trip and a cast error
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, order.ErrNotFound
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var s order.Snapshot
+	var status string
+	err := r.pool.QueryRow(ctx, `
+		SELECT id::text, customer_id, currency, status, version, created_at, updated_at
+		FROM orders
+		WHERE id = $1`, id,
+	).Scan(&s.ID, &s.CustomerID, &s.Currency, &status, &s.Version, &s.CreatedAt, &s.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, order.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find order %s: %w", id, err)
+	}
+	s.Status = order.Status(status)
+
+	lines, err := r.loadLines(ctx, []string{s.ID})
+	if err != nil {
+		return nil, err
+	}
+	s.Lines = lines[s.ID]
+
+	return order.Restore(s)
+}
+
+// List implements port.OrderRepository.
+func (r *OrderRepository) List(ctx context.Context, filter port.OrderFilter) ([]*order.Order, int, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var (
+		where []string
+		args  []any
+	)
+	if filter.CustomerID != "" {
+		args = append(args, filter.CustomerID)
+		where = append(where, "customer_id = $"+strconv.Itoa(len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		where = append(where, "status = $"+strconv.Itoa(len(args)))
+	}
+
+	query := `SELECT id::text, customer_id, currency, status, version, created_at, updated_at, count(*) OVER ()
+		FROM orders`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += " OFFSET $" + strconv.Itoa(len(args))
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list orders: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		snapshots []order.Snapshot
+		ids       []string
+		total     int
+	)
+	for rows.Next() {
+		var s order.Snapshot
+		var status string
+		if err := rows.Scan(&s.ID, &s.CustomerID, &s.Currency, &status, &s.Version, &s.CreatedAt, &s.UpdatedAt, &total); err != nil {
+			return nil, 0, fmt.Errorf("scan order: %w", err)
+		}
+		s.Status = order.Status(status)
+		snapshots = append(snapshots, s)
+		ids = append(ids, s.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("list orders: %w", err)
+	}
+
+	// count(*) OVER () is only available when the page is non-empty
+	if len(snapshots) == 0 && filter.Offset > 0 {
+		countQuery := "SELECT count(*) FROM orders"
+		if len(where) > 0 {
+			countQuery += " WHERE " + strings.Join(where, " AND ")
+		}
+		if err := r.pool.QueryRow(ctx, countQuery, args[:len(where)]...).Scan(&total); err != nil {
+			return nil, 0, fmt.Errorf("count orders: %w", err)
+		}
+	}
+
+	lines, err := r.loadLines(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]*order.Order, 0, len(snapshots))
+	for _, s := range snapshots {
+		s.Lines = lines[s.ID]
+		o, err := order.Restore(s)
+		if err != nil {
+			return nil, 0, err
+		}
+		result = append(result, o)
+	}
+
+	return result, total, nil
+}
+
+// loadLines returns the line items of the given orders keyed by order ID.
+func (r *OrderRepository) loadLines(ctx context.Context, orderIDs []string) (map[string][]order.LineItem, error) {
+	lines := make(map[string][]order.LineItem, len(orderIDs))
+	if len(orderIDs) == 0 {
+		return lines, nil
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT order_id::text, product_id, name, quantity, unit_price
+		FROM order_lines
+		WHERE order_id = ANY($1::uuid[])
+		ORDER BY order_id, line_no`, orderIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("load order lines: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID string
+		var line order.LineItem
+		if err := rows.Scan(&orderID, &line.ProductID, &line.Name, &line.Quantity, &line.UnitPrice); err != nil {
+			return nil, fmt.Errorf("scan order line: %w", err)
+		}
+		lines[orderID] = append(lines[orderID], line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("load order lines: %w", err)
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,242 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/domain/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newOrder returns a new (unsaved) order, with its order.created event.
+func newOrder(t *testing.T, customerID string) *order.Order {
+	t.Helper()
+
+	o, err := order.New(customerID, "USD", []order.LineItem{
+		{ProductID: "sku-1", Name: "Widget", Quantity: 1, UnitPrice: 1000},
+	})
+	require.NoError(t, err)
+	return o
+}
+
+// storedOrder returns a new (unsaved) order created at a given time, so list
+// ordering does not depend on the clock.
+func storedOrder(t *testing.T, customerID string, status order.Status, createdAt time.Time) *order.Order {
+	t.Helper()
+
+	o, err := order.Restore(order.Snapshot{
+		ID:         uuid.NewString(),
+		CustomerID: customerID,
+		Currency:   "USD",
+		Status:     status,
+		Lines: []order.LineItem{
+			{ProductID: "sku-1", Name: "Widget", Quantity: 1, UnitPrice: 1000},
+		},
+		CreatedAt: createdAt.UTC().Truncate(time.Microsecond),
+		UpdatedAt: createdAt.UTC().Truncate(time.Microsecond),
+	})
+	require.NoError(t, err)
+	return o
+}
+
+func TestOrderRepository_SaveAndFindByID(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository(newMigratedPool(t), 5*time.Second)
+
+	o, err := order.New("customer-1", "usd", []order.LineItem{
+		{ProductID: "sku-1", Name: "Widget", Quantity: 2, UnitPrice: 1500},
+		{ProductID: "sku-2", Name: "Gadget", Quantity: 1, UnitPrice: 999},
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.Save(ctx, o))
+	assert.Equal(t, 1, o.Version())
+	assert.Empty(t, o.Events())
+
+	found, err := repo.FindByID(ctx, o.ID())
+	require.NoError(t, err)
+	assert.Equal(t, o.ID(), found.ID())
+	assert.Equal(t, "customer-1", found.CustomerID())
+	assert.Equal(t, "USD", found.Currency())
+	assert.Equal(t, order.StatusPending, found.Status())
+	assert.Equal(t, o.Lines(), found.Lines())
+	assert.Equal(t, int64(3999), found.Total())
+	assert.Equal(t, 1, found.Version())
+	assert.WithinDuration(t, o.CreatedAt(), found.CreatedAt(), time.Microsecond)
+}
+
+func TestOrderRepository_FindByIDNotFound(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository(newMigratedPool(t), 0)
+
+	_, err := repo.FindByID(ctx, uuid.NewString())
+	assert.ErrorIs(t, err, order.ErrNotFound)
+
+	_, err = repo.FindByID(ctx, "not-a-uuid")
+	assert.ErrorIs(t, err, order.ErrNotFound)
+}
+
+func TestOrderRepository_SaveReplacesLines(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository(newMigratedPool(t), 0)
+
+	o := storedOrder(t, "customer-1", order.StatusPending, time.Now())
+	require.NoError(t, repo.Save(ctx, o))
+
+	require.NoError(t, o.AddLine(order.LineItem{ProductID: "sku-2", Name: "Gadget", Quantity: 3, UnitPrice: 250}))
+	require.NoError(t, o.AddLine(order.LineItem{ProductID: "sku-3", Name: "Gizmo", Quantity: 1, UnitPrice: 100}))
+	require.NoError(t, repo.Save(ctx, o))
+
+	require.NoError(t, o.RemoveLine("sku-1"))
+	require.NoError(t, repo.Save(ctx, o))
+
+	found, err := repo.FindByID(ctx, o.ID())
+	require.NoError(t, err)
+	assert.Equal(t, []order.LineItem{
+		{ProductID: "sku-2", Name: "Gadget", Quantity: 3, UnitPrice: 250},
+		{ProductID: "sku-3", Name: "Gizmo", Quantity: 1, UnitPrice: 100},
+	}, found.Lines())
+	assert.Equal(t, 3, found.Version())
+}
+
+func TestOrderRepository_SaveRejectsStaleVersion(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository(newMigratedPool(t), 0)
+
+	o := newOrder(t, "customer-1")
+	require.NoError(t, repo.Save(ctx, o))
+
+	cancelled, err := repo.FindByID(ctx, o.ID())
+	require.NoError(t, err)
+	confirmed, err := repo.FindByID(ctx, o.ID())
+	require.NoError(t, err)
+
+	require.NoError(t, cancelled.Cancel())
+	require.NoError(t, repo.Save(ctx, cancelled))
+
+	require.NoError(t, confirmed.Confirm())
+	require.ErrorIs(t, repo.Save(ctx, confirmed), order.ErrConcurrentModification)
+
+	found, err := repo.FindByID(ctx, o.ID())
+	require.NoError(t, err)
+	assert.Equal(t, order.StatusCancelled, found.Status())
+	assert.Equal(t, 2, found.Version())
+
+	// The rejected save wrote no event: order.created and order.cancelled only
+	var events []string
+	rows, err := repo.pool.Query(ctx, `SELECT event_type FROM outbox ORDER BY seq`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var eventType string
+		require.NoError(t, rows.Scan(&eventType))
+		events = append(events, eventType)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{string(order.EventOrderCreated), string(order.EventOrderCancelled)}, events)
+}
+
+func TestOrderRepository_SaveRejectsDuplicateID(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository(newMigratedPool(t), 0)
+
+	o := storedOrder(t, "customer-1", order.StatusPending, time.Now())
+	require.NoError(t, repo.Save(ctx, o))
+
+	duplicate, err := order.Restore(order.Snapshot{
+		ID:         o.ID(),
+		CustomerID: "customer-2",
+		Currency:   "EUR",
+		Status:     order.StatusPending,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	})
+	require.NoError(t, err)
+	require.ErrorIs(t, repo.Save(ctx, duplicate), order.ErrConcurrentModification)
+
+	found, err := repo.FindByID(ctx, o.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "customer-1", found.CustomerID())
+}
+
+func TestOrderRepository_List(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository(newMigratedPool(t), 0)
+
+	base := time.Now().Add(-time.Hour)
+	var ids []string
+	for i, fixture := range []struct {
+		customerID string
+		status     order.Status
+	}{
+		{"customer-1", order.StatusPending},
+		{"customer-1", order.StatusConfirmed},
+		{"customer-2", order.StatusPending},
+		{"customer-1", order.StatusPending},
+	} {
+		o := storedOrder(t, fixture.customerID, fixture.status, base.Add(time.Duration(i)*time.Minute))
+		require.NoError(t, repo.Save(ctx, o))
+		ids = append(ids, o.ID())
+	}
+
+	orderIDs := func(orders []*order.Order) []string {
+		out := make([]string, len(orders))
+		for i, o := range orders {
+			out[i] = o.ID()
+		}
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		filter    port.OrderFilter
+		wantIDs   []string
+		wantTotal int
+	}{
+		{
+			name:      "all newest first",
+			filter:    port.OrderFilter{},
+			wantIDs:   []string{ids[3], ids[2], ids[1], ids[0]},
+			wantTotal: 4,
+		},
+		{
+			name:      "by customer",
+			filter:    port.OrderFilter{CustomerID: "customer-1"},
+			wantIDs:   []string{ids[3], ids[1], ids[0]},
+			wantTotal: 3,
+		},
+		{
+			name:      "by customer and status",
+			filter:    port.OrderFilter{CustomerID: "customer-1", Status: order.StatusPending},
+			wantIDs:   []string{ids[3], ids[0]},
+			wantTotal: 2,
+		},
+		{
+			name:      "page",
+			filter:    port.OrderFilter{Limit: 2, Offset: 1},
+			wantIDs:   []string{ids[2], ids[1]},
+			wantTotal: 4,
+		},
+		{
+			name:      "offset past the end",
+			filter:    port.OrderFilter{CustomerID: "customer-1", Limit: 10, Offset: 5},
+			wantIDs:   []string{},
+			wantTotal: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders, total, err := repo.List(ctx, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantIDs, orderIDs(orders))
+			assert.Equal(t, tt.wantTotal, total)
+			for _, o := range orders {
+				assert.Len(t, o.Lines(), 1, "lines of order %s", o.ID())
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// insertOutboxMessages writes messages to the outbox within tx.
+//
+// Repositories call it after updating the aggregate row: the row lock taken by
+// that update makes concurrent writers of the same aggregate wait, so outbox
+// sequence numbers follow the commit order of each aggregate. It does not stop
+// stale writes; the version check of the update does (see OrderRepository.Save).
+func insertOutboxMessages(ctx context.Context, tx pgx.Tx, messages []port.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, msg := range messages {
+		batch.Queue(`
+			INSERT INTO outbox (id, aggregate_type, aggregate_id, event_type, payload, occurred_at)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			msg.ID, msg.AggregateType, msg.AggregateID, msg.Type, msg.Payload, msg.OccurredAt,
+		)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to insert outbox messages: %w", err)
+	}
+	return nil
+}
+
+// Outbox is a PostgreSQL implementation of port.OutboxStore.
+// Claims use FOR UPDATE SKIP LOCKED and a lease, so several relays
+// (one per replica) can run concurrently without publishing a message twice
+// within the lease.
+type Outbox struct {
+	pool         *pgxpool.Pool
+	queryTimeout time.Duration
+}
+
+// Compile-time check that Outbox implements port.OutboxStore.
+var _ port.OutboxStore = (*Outbox)(nil)
+
+// NewOutbox creates a PostgreSQL outbox store.
+//
+// Parameters:
+//   - pool: The connection pool
+//   - queryTimeout: Maximum duration of each store call (0 disables the timeout)
+//
+// Returns:
+//   - *Outbox: The outbox store
+func NewOutbox(pool *pgxpool.Pool, queryTimeout time.Duration) *Outbox {
+	return &Outbox{
+		pool:         pool,
+		queryTimeout: queryTimeout,
+	}
+}
+
+// withTimeout derives a context bounded by the configured query timeout.
+func (o *Outbox) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.queryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, o.queryTimeout)
+}
+
+// ClaimPending implements port.OutboxStore.
+func (o *Outbox) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]port.OutboxMessage, error) {
+	ctx, cancel := o.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := o.pool.Query(ctx, `
+		UPDATE outbox SET locked_until = now() + $2::interval
+		WHERE id IN (
+			SELECT m.id
+			FROM outbox m
+			WHERE m.next_attempt_at <= now()
+				AND (m.locked_until IS NULL OR m.locked_until <= now())
+				AND NOT EXISTS (
+					SELECT 1 FROM outbox p
+					WHERE p.aggregate_id = m.aggregate_id AND p.seq < m.seq
+				)
+			ORDER BY m.seq
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING seq, id::text, aggregate_type, aggregate_id, event_type, payload, occurred_at, attempts`,
+		limit, lease,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("claim outbox messages: %w", err)
+	}
+	defer rows.Close()
+
+	type claimed struct {
+		seq int64
+		msg port.OutboxMessage
+	}
+	var result []claimed
+	for rows.Next() {
+		var c claimed
+		if err := rows.Scan(&c.seq, &c.msg.ID, &c.msg.AggregateType, &c.msg.AggregateID, &c.msg.Type,
+			&c.msg.Payload, &c.msg.OccurredAt, &c.msg.Attempts); err != nil {
+			return nil, fmt.Errorf("scan outbox message: %w", err)
+		}
+		result = append(result, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("claim outbox messages: %w", err)
+	}
+
+	// RETURNING does not preserve the subquery order
+	sort.Slice(result, func(i, j int) bool { return result[i].seq < result[j].seq })
+
+	messages := make([]port.OutboxMessage, len(result))
+	for i, c := range result {
+		messages[i] = c.msg
+	}
+	return messages, nil
+}
+
+// MarkPublished implements port.OutboxStore.
+// Published messages are deleted, so the table only holds pending messages.
+func (o *Outbox) MarkPublished(ctx context.Context, id string) error {
+	ctx, cancel := o.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := o.pool.Exec(ctx, `DELETE FROM outbox WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("mark outbox message %s published: %w", id, err)
+	}
+	return nil
+}
+
+// MarkFailed implements port.OutboxStore.
+func (o *Outbox) MarkFailed(ctx context.Context, id string, nextAttemptAt time.Time, cause error) error {
+	ctx, cancel := o.withTimeout(ctx)
+	defer cancel()
+
+	var lastError string
+	if cause != nil {
+		lastError = cause.Error()
+	}
+
+	if _, err := o.pool.Exec(ctx, `
+		UPDATE outbox SET
+			attempts        = attempts + 1,
+			next_attempt_at = $2,
+			locked_until    = NULL,
+			last_error      = $3
+		WHERE id = $1`, id, nextAttemptAt, lastError,
+	); err != nil {
+		return fmt.Errorf("mark outbox message %s failed: %w", id, err)
+	}
+	return nil
+}
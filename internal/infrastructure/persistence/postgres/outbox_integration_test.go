@@ -0,0 +1,123 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/domain/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutbox_ClaimPendingOneHeadPerAggregate(t *testing.T) {
+	ctx := context.Background()
+	pool := newMigratedPool(t)
+	repo := NewOrderRepository(pool, 0)
+	outbox := NewOutbox(pool, 0)
+
+	// first: created, confirmed, paid; second: created
+	first := newOrder(t, "customer-1")
+	require.NoError(t, repo.Save(ctx, first))
+	require.NoError(t, first.Confirm())
+	require.NoError(t, first.MarkPaid())
+	require.NoError(t, repo.Save(ctx, first))
+
+	second := newOrder(t, "customer-2")
+	require.NoError(t, repo.Save(ctx, second))
+
+	var published []string
+	for range 4 {
+		claimed, err := outbox.ClaimPending(ctx, 10, time.Minute)
+		require.NoError(t, err)
+		require.NotEmpty(t, claimed)
+
+		// Oldest message of each aggregate only, in sequence order
+		seen := make(map[string]bool)
+		for _, msg := range claimed {
+			assert.False(t, seen[msg.AggregateID], "two messages of aggregate %s claimed", msg.AggregateID)
+			seen[msg.AggregateID] = true
+			require.NoError(t, outbox.MarkPublished(ctx, msg.ID))
+			if msg.AggregateID == first.ID() {
+				published = append(published, msg.Type)
+			}
+		}
+		if len(published) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []string{
+		string(order.EventOrderCreated),
+		string(order.EventOrderConfirmed),
+		string(order.EventOrderPaid),
+	}, published)
+
+	claimed, err := outbox.ClaimPending(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+}
+
+func TestOutbox_ClaimPendingLease(t *testing.T) {
+	ctx := context.Background()
+	pool := newMigratedPool(t)
+	repo := NewOrderRepository(pool, 0)
+	outbox := NewOutbox(pool, 0)
+
+	o := newOrder(t, "customer-1")
+	require.NoError(t, repo.Save(ctx, o))
+
+	claimed, err := outbox.ClaimPending(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	// Leased messages are hidden from other relays
+	again, err := outbox.ClaimPending(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+
+	// An expired lease makes the message claimable again
+	_, err = pool.Exec(ctx, `UPDATE outbox SET locked_until = now() - interval '1 second'`)
+	require.NoError(t, err)
+	again, err = outbox.ClaimPending(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, again, 1)
+	assert.Equal(t, claimed[0].ID, again[0].ID)
+}
+
+func TestOutbox_MarkFailedSchedulesRetry(t *testing.T) {
+	ctx := context.Background()
+	pool := newMigratedPool(t)
+	repo := NewOrderRepository(pool, 0)
+	outbox := NewOutbox(pool, 0)
+
+	o := newOrder(t, "customer-1")
+	require.NoError(t, repo.Save(ctx, o))
+	require.NoError(t, o.Confirm())
+	require.NoError(t, repo.Save(ctx, o))
+
+	claimed, err := outbox.ClaimPending(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.NoError(t, outbox.MarkFailed(ctx, claimed[0].ID, time.Now().Add(time.Hour), errors.New("broker down")))
+
+	// The failed head is not due, and blocks the later events of its aggregate
+	again, err := outbox.ClaimPending(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+
+	_, err = pool.Exec(ctx, `UPDATE outbox SET next_attempt_at = now()`)
+	require.NoError(t, err)
+	again, err = outbox.ClaimPending(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, again, 1)
+	assert.Equal(t, claimed[0].ID, again[0].ID)
+	assert.Equal(t, 1, again[0].Attempts)
+	assert.Equal(t, string(order.EventOrderCreated), again[0].Type)
+
+	var lastError string
+	require.NoError(t, pool.QueryRow(ctx, `SELECT last_error FROM outbox WHERE id = $1`, claimed[0].ID).Scan(&lastError))
+	assert.Equal(t, "broker down", lastError)
+}
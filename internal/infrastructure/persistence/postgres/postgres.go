@@ -0,0 +1,171 @@
+// Package postgres provides PostgreSQL implementations of the persistence ports
+// using the pgx driver and its connection pool.
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/infrastructure/config"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationLockID is the advisory lock key held while applying migrations,
+// so several replicas starting at once do not race.
+const migrationLockID = 7_310_411_252
+
+// NewPool creates a pgx connection pool and verifies connectivity.
+//
+// Parameters:
+//   - ctx: Context for the initial connection
+//   - cfg: Database configuration
+//
+// Returns:
+//   - *pgxpool.Pool: The connection pool (must be closed)
+//   - error: Any error parsing the DSN or connecting
+func NewPool(ctx context.Context, cfg config.DatabaseConfig) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.DSN.Value())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database DSN: %w", err)
+	}
+
+	if cfg.MaxConns > 0 {
+		poolCfg.MaxConns = cfg.MaxConns
+	}
+	if cfg.MinConns > 0 {
+		poolCfg.MinConns = cfg.MinConns
+	}
+	if cfg.MaxConnLifetime > 0 {
+		poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.ConnectTimeout > 0 {
+		poolCfg.ConnConfig.ConnectTimeout = cfg.ConnectTimeout
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database pool: %w", err)
+	}
+
+	pingCtx := ctx
+	if cfg.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		pingCtx, cancel = context.WithTimeout(ctx, cfg.ConnectTimeout)
+		defer cancel()
+	}
+	if err := pool.Ping(pingCtx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return pool, nil
+}
+
+// HealthChecker checks database connectivity. It implements port.HealthChecker.
+type HealthChecker struct {
+	pool *pgxpool.Pool
+}
+
+// Compile-time check that HealthChecker implements port.HealthChecker.
+var _ port.HealthChecker = (*HealthChecker)(nil)
+
+// NewHealthChecker creates a database health checker.
+//
+// Parameters:
+//   - pool: The connection pool to check
+//
+// Returns:
+//   - *HealthChecker: The health checker
+func NewHealthChecker(pool *pgxpool.Pool) *HealthChecker {
+	return &HealthChecker{pool: pool}
+}
+
+// Name implements port.HealthChecker.
+func (h *HealthChecker) Name() string {
+	return "postgres"
+}
+
+// Check implements port.HealthChecker.
+func (h *HealthChecker) Check(ctx context.Context) error {
+	return h.pool.Ping(ctx)
+}
+
+// Migrate applies all pending *.up.sql migrations from fsys in version order.
+// Applied versions are recorded in the schema_migrations table and each
+// migration runs in its own transaction.
+//
+// Parameters:
+//   - ctx: Context for the migration run
+//   - pool: The connection pool
+//   - fsys: Filesystem containing the migration files (e.g., migrations.FS)
+//
+// Returns:
+//   - []string: The versions applied by this run
+//   - error: Any error reading or applying migrations
+func Migrate(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS) ([]string, error) {
+	files, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	sort.Strings(files)
+
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.Exec(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+
+	if _, err := conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    TEXT        PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var applied []string
+	for _, file := range files {
+		version, _, _ := strings.Cut(file, "_")
+
+		var exists bool
+		if err := conn.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, version,
+		).Scan(&exists); err != nil {
+			return applied, fmt.Errorf("failed to check migration %s: %w", version, err)
+		}
+		if exists {
+			continue
+		}
+
+		sql, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return applied, fmt.Errorf("failed to read migration %s: %w", file, err)
+		}
+
+		if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, string(sql)); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version)
+			return err
+		}); err != nil {
+			return applied, fmt.Errorf("failed to apply migration %s: %w", file, err)
+		}
+
+		applied = append(applied, version)
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,94 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hapkiduki/order-go/migrations"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDSNEnv names the environment variable with the DSN of the test database
+// (e.g., the one started by make dev-infra). Tests are skipped without it.
+const testDSNEnv = "TEST_DATABASE_DSN"
+
+// newTestPool returns a pool whose connections use a fresh schema, dropped when
+// the test ends, so tests neither see nor leave any data.
+func newTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+	ctx := context.Background()
+
+	admin, err := pgxpool.New(ctx, dsn)
+	require.NoError(t, err)
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	_, err = admin.Exec(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+		admin.Close()
+	})
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	require.NoError(t, err)
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+// newMigratedPool returns a test pool with all migrations applied.
+func newMigratedPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	pool := newTestPool(t)
+	_, err := Migrate(context.Background(), pool, migrations.FS)
+	require.NoError(t, err)
+	return pool
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	pool := newTestPool(t)
+
+	applied, err := Migrate(ctx, pool, migrations.FS)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"000001", "000002", "000003", "000004"}, applied)
+
+	var tables int
+	require.NoError(t, pool.QueryRow(ctx, `
+		SELECT count(*) FROM information_schema.tables
+		WHERE table_schema = current_schema()
+			AND table_name IN ('orders', 'order_lines', 'outbox', 'schema_migrations')`,
+	).Scan(&tables))
+	assert.Equal(t, 4, tables)
+}
+
+func TestMigrate_RunTwice(t *testing.T) {
+	ctx := context.Background()
+	pool := newTestPool(t)
+
+	_, err := Migrate(ctx, pool, migrations.FS)
+	require.NoError(t, err)
+
+	applied, err := Migrate(ctx, pool, migrations.FS)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	var versions int
+	require.NoError(t, pool.QueryRow(ctx, `SELECT count(*) FROM schema_migrations`).Scan(&versions))
+	assert.Equal(t, 4, versions)
+}
@@ -0,0 +1,113 @@
+// Package redis provides Redis implementations of the application ports.
+// Redis-backed adapters share state across replicas, unlike their in-memory counterparts.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// idempotencyKeyPrefix namespaces idempotency keys in Redis.
+const idempotencyKeyPrefix = "order-go:idempotency:"
+
+// releaseScript deletes a reservation only if it has not been completed,
+// so a late Release never removes a stored response.
+var releaseScript = goredis.NewScript(`
+local value = redis.call("GET", KEYS[1])
+if not value then
+	return 0
+end
+local record = cjson.decode(value)
+if record["completed"] then
+	return 0
+end
+return redis.call("DEL", KEYS[1])
+`)
+
+// IdempotencyStore is a Redis implementation of port.IdempotencyStore.
+// Records are stored as JSON with a TTL; reservations use SET NX for atomicity.
+type IdempotencyStore struct {
+	client goredis.UniversalClient
+}
+
+// Compile-time check that IdempotencyStore implements port.IdempotencyStore.
+var _ port.IdempotencyStore = (*IdempotencyStore)(nil)
+
+// NewIdempotencyStore creates a Redis-backed idempotency store.
+//
+// Parameters:
+//   - client: The Redis client
+//
+// Returns:
+//   - *IdempotencyStore: The store
+func NewIdempotencyStore(client goredis.UniversalClient) *IdempotencyStore {
+	return &IdempotencyStore{client: client}
+}
+
+// Reserve implements port.IdempotencyStore.
+func (s *IdempotencyStore) Reserve(
+	ctx context.Context, key, fingerprint string, lockTTL time.Duration,
+) (*port.IdempotencyRecord, bool, error) {
+	reservation, err := json.Marshal(port.IdempotencyRecord{
+		Fingerprint: fingerprint,
+		CreatedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	acquired, err := s.client.SetNX(ctx, idempotencyKeyPrefix+key, reservation, lockTTL).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+	if acquired {
+		return nil, true, nil
+	}
+
+	value, err := s.client.Get(ctx, idempotencyKeyPrefix+key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		// The key expired between SETNX and GET; try to reserve it again
+		return s.Reserve(ctx, key, fingerprint, lockTTL)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("get idempotency key: %w", err)
+	}
+
+	var record port.IdempotencyRecord
+	if err := json.Unmarshal(value, &record); err != nil {
+		return nil, false, fmt.Errorf("decode idempotency record: %w", err)
+	}
+	return &record, false, nil
+}
+
+// Complete implements port.IdempotencyStore.
+func (s *IdempotencyStore) Complete(
+	ctx context.Context, key string, record port.IdempotencyRecord, ttl time.Duration,
+) error {
+	record.Completed = true
+
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.Set(ctx, idempotencyKeyPrefix+key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("store idempotency record: %w", err)
+	}
+	return nil
+}
+
+// Release implements port.IdempotencyStore.
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	if err := releaseScript.Run(ctx, s.client, []string{idempotencyKeyPrefix + key}).Err(); err != nil &&
+		!errors.Is(err, goredis.Nil) {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
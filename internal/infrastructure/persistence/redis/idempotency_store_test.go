@@ -0,0 +1,113 @@
+package redis
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyStore_Reserve(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestClient(t)
+	store := NewIdempotencyStore(client)
+
+	existing, acquired, err := store.Reserve(ctx, "key", "fingerprint-a", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	assert.Nil(t, existing)
+
+	// A second request sees the in-flight reservation
+	existing, acquired, err = store.Reserve(ctx, "key", "fingerprint-b", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+	require.NotNil(t, existing)
+	assert.Equal(t, "fingerprint-a", existing.Fingerprint)
+	assert.False(t, existing.Completed)
+}
+
+func TestIdempotencyStore_ReservationExpires(t *testing.T) {
+	ctx := context.Background()
+	server, client := newTestClient(t)
+	store := NewIdempotencyStore(client)
+
+	_, acquired, err := store.Reserve(ctx, "key", "fingerprint", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	server.FastForward(time.Minute)
+
+	_, acquired, err = store.Reserve(ctx, "key", "fingerprint", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired, "a crashed request does not hold its key forever")
+}
+
+func TestIdempotencyStore_Complete(t *testing.T) {
+	ctx := context.Background()
+	server, client := newTestClient(t)
+	store := NewIdempotencyStore(client)
+
+	_, _, err := store.Reserve(ctx, "key", "fingerprint", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, store.Complete(ctx, "key", port.IdempotencyRecord{
+		Fingerprint: "fingerprint",
+		StatusCode:  http.StatusCreated,
+		Header:      map[string][]string{"Content-Type": {"application/json"}},
+		Body:        []byte(`{"success":true}`),
+	}, 24*time.Hour))
+
+	existing, acquired, err := store.Reserve(ctx, "key", "fingerprint", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+	require.NotNil(t, existing)
+	assert.True(t, existing.Completed)
+	assert.Equal(t, http.StatusCreated, existing.StatusCode)
+	assert.Equal(t, []string{"application/json"}, existing.Header["Content-Type"])
+	assert.JSONEq(t, `{"success":true}`, string(existing.Body))
+
+	// The response outlives the lock
+	assert.Equal(t, 24*time.Hour, server.TTL(idempotencyKeyPrefix+"key"))
+}
+
+func TestIdempotencyStore_Release(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestClient(t)
+	store := NewIdempotencyStore(client)
+
+	_, _, err := store.Reserve(ctx, "key", "fingerprint", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, store.Release(ctx, "key"))
+
+	_, acquired, err := store.Reserve(ctx, "key", "fingerprint", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired, "a released key can be retried")
+
+	// Releasing an unknown key is not an error
+	assert.NoError(t, store.Release(ctx, "unknown"))
+}
+
+func TestIdempotencyStore_ReleaseKeepsCompleted(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestClient(t)
+	store := NewIdempotencyStore(client)
+
+	_, _, err := store.Reserve(ctx, "key", "fingerprint", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, store.Complete(ctx, "key", port.IdempotencyRecord{
+		Fingerprint: "fingerprint",
+		StatusCode:  http.StatusOK,
+	}, time.Hour))
+
+	// A late release never removes a stored response
+	require.NoError(t, store.Release(ctx, "key"))
+
+	existing, acquired, err := store.Reserve(ctx, "key", "fingerprint", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+	require.NotNil(t, existing)
+	assert.True(t, existing.Completed)
+}
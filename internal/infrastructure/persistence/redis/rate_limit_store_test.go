@@ -0,0 +1,138 @@
+package redis
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitStore_Burst(t *testing.T) {
+	ctx := context.Background()
+	server, client := newTestClient(t)
+	server.SetTime(time.Now())
+	store := NewRateLimitStore(client)
+	limit := port.RateLimit{Rate: 1, Burst: 3}
+
+	for i := range 3 {
+		result, err := store.Allow(ctx, "client-a", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed, "request %d", i+1)
+		assert.Zero(t, result.RetryAfter)
+	}
+
+	result, err := store.Allow(ctx, "client-a", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+
+	// Buckets are per key
+	result, err = store.Allow(ctx, "client-b", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+}
+
+func TestRateLimitStore_Refill(t *testing.T) {
+	ctx := context.Background()
+	server, client := newTestClient(t)
+	now := time.Now()
+	server.SetTime(now)
+	store := NewRateLimitStore(client)
+	limit := port.RateLimit{Rate: 2, Burst: 2}
+
+	for range 2 {
+		result, err := store.Allow(ctx, "client", limit)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+	result, err := store.Allow(ctx, "client", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+
+	// One token comes back every 1/rate seconds
+	server.SetTime(now.Add(500 * time.Millisecond))
+	result, err = store.Allow(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	result, err = store.Allow(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+
+	// An idle bucket refills up to the burst, not beyond
+	server.SetTime(now.Add(time.Minute))
+	for range 2 {
+		result, err = store.Allow(ctx, "client", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+	result, err = store.Allow(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+}
+
+func TestRateLimitStore_RetryAfter(t *testing.T) {
+	ctx := context.Background()
+	server, client := newTestClient(t)
+	now := time.Now()
+	server.SetTime(now)
+	store := NewRateLimitStore(client)
+	limit := port.RateLimit{Rate: 4, Burst: 1}
+
+	result, err := store.Allow(ctx, "client", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	result, err = store.Allow(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 250*time.Millisecond, result.RetryAfter)
+
+	// Rejected requests do not consume tokens, so the wait only shrinks
+	server.SetTime(now.Add(100 * time.Millisecond))
+	result, err = store.Allow(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 150*time.Millisecond, result.RetryAfter)
+}
+
+func TestRateLimitStore_KeyExpires(t *testing.T) {
+	ctx := context.Background()
+	server, client := newTestClient(t)
+	server.SetTime(time.Now())
+	store := NewRateLimitStore(client)
+
+	_, err := store.Allow(ctx, "client", port.RateLimit{Rate: 10, Burst: 5})
+	require.NoError(t, err)
+
+	// The key lives until the bucket would be full again
+	ttl := server.TTL(rateLimitKeyPrefix + "client")
+	assert.Greater(t, ttl, time.Duration(0))
+	assert.LessOrEqual(t, ttl, 100*time.Millisecond)
+}
+
+func TestRateLimitStore_Limits(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestClient(t)
+	store := NewRateLimitStore(client)
+
+	result, err := store.Allow(ctx, "client", port.RateLimit{Rate: 0, Burst: 10})
+	require.NoError(t, err)
+	assert.False(t, result.Allowed, "zero rate blocks every request")
+
+	result, err = store.Allow(ctx, "client", port.RateLimit{Rate: math.Inf(1), Burst: 1})
+	require.NoError(t, err)
+	assert.True(t, result.Allowed, "infinite rate allows every request")
+}
+
+func TestRateLimitStore_Unavailable(t *testing.T) {
+	server, client := newTestClient(t)
+	store := NewRateLimitStore(client)
+	server.Close()
+
+	_, err := store.Allow(context.Background(), "client", port.RateLimit{Rate: 1, Burst: 1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/infrastructure/config"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// NewClient creates a Redis client and verifies connectivity.
+//
+// Parameters:
+//   - ctx: Context for the initial ping
+//   - cfg: Redis configuration
+//
+// Returns:
+//   - *goredis.Client: The client (must be closed)
+//   - error: Any error connecting to Redis
+func NewClient(ctx context.Context, cfg config.RedisConfig) (*goredis.Client, error) {
+	client := goredis.NewClient(&goredis.Options{
+		Addr:         cfg.Addr,
+		Password:     cfg.Password.Value(),
+		DB:           cfg.DB,
+		PoolSize:     cfg.PoolSize,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+	})
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return client, nil
+}
+
+// HealthChecker checks Redis connectivity. It implements port.HealthChecker.
+type HealthChecker struct {
+	client goredis.UniversalClient
+}
+
+// Compile-time check that HealthChecker implements port.HealthChecker.
+var _ port.HealthChecker = (*HealthChecker)(nil)
+
+// NewHealthChecker creates a Redis health checker.
+//
+// Parameters:
+//   - client: The Redis client to check
+//
+// Returns:
+//   - *HealthChecker: The health checker
+func NewHealthChecker(client goredis.UniversalClient) *HealthChecker {
+	return &HealthChecker{client: client}
+}
+
+// Name implements port.HealthChecker.
+func (h *HealthChecker) Name() string {
+	return "redis"
+}
+
+// Check implements port.HealthChecker.
+func (h *HealthChecker) Check(ctx context.Context) error {
+	return h.client.Ping(ctx).Err()
+}
@@ -0,0 +1,251 @@
+// Package tracing provides an OpenTelemetry implementation of the port.Tracer interface.
+//
+// Setup installs a global TracerProvider and the W3C trace context propagator
+// (traceparent/tracestate plus baggage), so any OpenTelemetry-instrumented library
+// used by the service joins the same traces.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/infrastructure/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Supported exporters.
+const (
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+// instrumentationName identifies the tracer created by this package.
+const instrumentationName = "github.com/hapkiduki/order-go"
+
+// ShutdownFunc flushes pending spans and releases exporter resources.
+type ShutdownFunc func(ctx context.Context) error
+
+// ServiceInfo describes the service in every exported span.
+type ServiceInfo struct {
+	// Name is the service name (e.g., "order-go")
+	Name string
+
+	// Version is the service version
+	Version string
+
+	// Environment is the deployment environment (e.g., "production")
+	Environment string
+}
+
+// Setup configures the global OpenTelemetry TracerProvider and propagator.
+// When tracing is disabled the W3C propagator is still installed, so incoming
+// trace context is forwarded even though no spans are recorded.
+//
+// Parameters:
+//   - ctx: Context for exporter initialization
+//   - cfg: Tracing configuration
+//   - service: Service identification added as resource attributes
+//
+// Returns:
+//   - ShutdownFunc: Flushes and stops the exporter; call before exit
+//   - error: Invalid exporter or exporter initialization error
+func Setup(ctx context.Context, cfg config.TracingConfig, service ServiceInfo) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closeOutput, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(service.Name),
+		semconv.ServiceVersion(service.Version),
+		semconv.DeploymentEnvironmentName(service.Environment),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeOutput != nil {
+			err = errors.Join(err, closeOutput.Close())
+		}
+		return err
+	}, nil
+}
+
+// newExporter creates the span exporter selected in the configuration.
+// The returned io.Closer is non-nil when the exporter owns an output file.
+func newExporter(ctx context.Context, cfg config.TracingConfig) (sdktrace.SpanExporter, io.Closer, error) {
+	switch cfg.Exporter {
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		return exporter, nil, err
+
+	case ExporterFile:
+		file, err := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return exporter, file, nil
+
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.OTLPEndpoint))
+		}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create otlp exporter: %w", err)
+		}
+		return exporter, nil, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown tracing exporter %q (want stdout, file or otlp)", cfg.Exporter)
+	}
+}
+
+// Tracer is an OpenTelemetry implementation of port.Tracer.
+// It uses the global TracerProvider, so it must be created after Setup.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// Compile-time checks that the adapters implement the ports.
+var (
+	_ port.Tracer = (*Tracer)(nil)
+	_ port.Span   = (*Span)(nil)
+)
+
+// NewTracer creates a Tracer backed by the global TracerProvider.
+//
+// Returns:
+//   - *Tracer: The tracer
+func NewTracer() *Tracer {
+	return &Tracer{tracer: otel.Tracer(instrumentationName)}
+}
+
+// StartSpan implements port.Tracer.
+func (t *Tracer) StartSpan(ctx context.Context, operationName string) (context.Context, port.Span) {
+	ctx, span := t.tracer.Start(ctx, operationName)
+	return ctx, &Span{span: span}
+}
+
+// StartServerSpan starts a span of kind server, used for incoming requests.
+//
+// Parameters:
+//   - ctx: Context carrying the extracted remote parent, if any
+//   - operationName: The span name
+//
+// Returns:
+//   - context.Context: Context containing the new span
+//   - port.Span: The span (must be ended)
+func (t *Tracer) StartServerSpan(ctx context.Context, operationName string) (context.Context, port.Span) {
+	ctx, span := t.tracer.Start(ctx, operationName, trace.WithSpanKind(trace.SpanKindServer))
+	return ctx, &Span{span: span}
+}
+
+// Span is an OpenTelemetry implementation of port.Span.
+type Span struct {
+	span trace.Span
+}
+
+// End implements port.Span.
+func (s *Span) End() {
+	s.span.End()
+}
+
+// SetAttribute implements port.Span.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	s.span.SetAttributes(toAttribute(key, value))
+}
+
+// SetError implements port.Span.
+func (s *Span) SetError(err error) {
+	if err == nil {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// AddEvent implements port.Span.
+func (s *Span) AddEvent(name string, attributes map[string]interface{}) {
+	attrs := make([]attribute.KeyValue, 0, len(attributes))
+	for k, v := range attributes {
+		attrs = append(attrs, toAttribute(k, v))
+	}
+	s.span.AddEvent(name, trace.WithAttributes(attrs...))
+}
+
+// SetName renames the span, e.g. once the matched route is known.
+//
+// Parameters:
+//   - name: The new span name
+func (s *Span) SetName(name string) {
+	s.span.SetName(name)
+}
+
+// toAttribute converts a value into a typed attribute, falling back to its string form.
+func toAttribute(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case float32:
+		return attribute.Float64(key, float64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
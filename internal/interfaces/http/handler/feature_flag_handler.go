@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/interfaces/http/response"
+)
+
+// FeatureFlagEvaluator evaluates every feature flag against a set of attributes.
+// It is satisfied by *featureflags.Provider.
+type FeatureFlagEvaluator interface {
+	EvaluateAll(fc port.FlagContext) []port.FlagEvaluation
+}
+
+// FeatureFlagHandler serves the /admin/feature-flags endpoints.
+type FeatureFlagHandler struct {
+	flags FeatureFlagEvaluator
+}
+
+// NewFeatureFlagHandler creates a new FeatureFlagHandler.
+//
+// Parameters:
+//   - flags: The feature flag evaluator
+//
+// Returns:
+//   - *FeatureFlagHandler: The handler
+func NewFeatureFlagHandler(flags FeatureFlagEvaluator) *FeatureFlagHandler {
+	return &FeatureFlagHandler{flags: flags}
+}
+
+// Routes returns a router with all feature flag endpoints.
+// Mount it under an authenticated prefix, e.g. r.Mount("/admin/feature-flags", h.Routes()).
+//
+// Returns:
+//   - chi.Router: The feature flag routes
+func (h *FeatureFlagHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.List)
+	return r
+}
+
+// flagContextResponse is the JSON representation of the evaluation attributes.
+type flagContextResponse struct {
+	TenantID    string `json:"tenant_id"`
+	UserID      string `json:"user_id"`
+	ClientIP    string `json:"client_ip"`
+	Environment string `json:"environment"`
+}
+
+// flagEvaluationResponse is the JSON representation of a flag evaluation.
+type flagEvaluationResponse struct {
+	Key         string `json:"key"`
+	Description string `json:"description,omitempty"`
+	Enabled     bool   `json:"enabled"`
+	Variant     string `json:"variant,omitempty"`
+	Reason      string `json:"reason"`
+}
+
+// listFlagsResponse is the JSON representation of all flag evaluations.
+type listFlagsResponse struct {
+	Context flagContextResponse      `json:"context"`
+	Flags   []flagEvaluationResponse `json:"flags"`
+}
+
+// List handles GET /feature-flags with optional tenant_id, user_id, client_ip
+// and environment query parameters. It evaluates every flag against those
+// attributes; the environment defaults to the one the server runs in.
+func (h *FeatureFlagHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	fc := port.FlagContext{
+		TenantID:    query.Get("tenant_id"),
+		UserID:      query.Get("user_id"),
+		ClientIP:    query.Get("client_ip"),
+		Environment: query.Get("environment"),
+	}
+	if fc.Environment == "" {
+		fc.Environment = port.FlagContextFrom(r.Context()).Environment
+	}
+
+	evals := h.flags.EvaluateAll(fc)
+	flags := make([]flagEvaluationResponse, 0, len(evals))
+	for _, eval := range evals {
+		flags = append(flags, flagEvaluationResponse{
+			Key:         eval.Key,
+			Description: eval.Description,
+			Enabled:     eval.Enabled,
+			Variant:     eval.Variant,
+			Reason:      eval.Reason,
+		})
+	}
+
+	response.Success(w, r, http.StatusOK, listFlagsResponse{
+		Context: flagContextResponse{
+			TenantID:    fc.TenantID,
+			UserID:      fc.UserID,
+			ClientIP:    fc.ClientIP,
+			Environment: fc.Environment,
+		},
+		Flags: flags,
+	})
+}
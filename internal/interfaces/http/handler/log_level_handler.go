@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/interfaces/http/middleware"
+	"github.com/hapkiduki/order-go/internal/interfaces/http/response"
+	"github.com/hapkiduki/order-go/pkg/logger"
+)
+
+// maxLogLevelRequestSize limits the body of log level changes.
+const maxLogLevelRequestSize = 1 << 10
+
+// LogLevelController reads and changes log levels at runtime.
+// It is satisfied by *logger.Logger.
+type LogLevelController interface {
+	Levels() []logger.LevelState
+	SetLevelFor(name, level string, ttl time.Duration) error
+	ResetLevel(name string)
+}
+
+// LogLevelHandler serves the /admin/log-level endpoints.
+type LogLevelHandler struct {
+	levels LogLevelController
+	logger port.Logger
+	audit  port.AuditLogger
+}
+
+// NewLogLevelHandler creates a new LogLevelHandler.
+//
+// Parameters:
+//   - levels: The log level controller
+//   - logger: The logger used to record level changes
+//   - audit: The audit trail of level changes (optional, may be nil)
+//
+// Returns:
+//   - *LogLevelHandler: The handler
+func NewLogLevelHandler(levels LogLevelController, logger port.Logger, audit port.AuditLogger) *LogLevelHandler {
+	return &LogLevelHandler{levels: levels, logger: logger, audit: audit}
+}
+
+// Routes returns a router with all log level endpoints.
+// Mount it under an authenticated prefix, e.g. r.Mount("/admin/log-level", h.Routes()).
+//
+// Returns:
+//   - chi.Router: The log level routes
+func (h *LogLevelHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.List)
+	r.Put("/", h.Set)
+	r.Delete("/", h.Reset)
+	r.Put("/{name}", h.Set)
+	r.Delete("/{name}", h.Reset)
+	return r
+}
+
+// setLogLevelRequest is the body of PUT /log-level[/{name}].
+type setLogLevelRequest struct {
+	Level string `json:"level"`
+
+	// TTL reverts the change after this duration (e.g., "10m"); empty is permanent
+	TTL string `json:"ttl"`
+}
+
+// logLevelResponse is the JSON representation of a log level.
+type logLevelResponse struct {
+	Logger    string     `json:"logger,omitempty"`
+	Level     string     `json:"level"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevertTo  string     `json:"revert_to,omitempty"`
+}
+
+// logLevelsResponse is the JSON representation of all log levels.
+type logLevelsResponse struct {
+	Global    logLevelResponse   `json:"global"`
+	Overrides []logLevelResponse `json:"overrides"`
+}
+
+// List handles GET /log-level.
+func (h *LogLevelHandler) List(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, r, http.StatusOK, h.levelsResponse())
+}
+
+// Set handles PUT /log-level (global level) and PUT /log-level/{name}
+// (level of a named logger and its children).
+func (h *LogLevelHandler) Set(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxLogLevelRequestSize)
+
+	var req setLogLevelRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		response.Error(w, r, response.InvalidJSON().WithCause(err))
+		return
+	}
+
+	var ttl time.Duration
+	if req.TTL != "" {
+		parsed, err := time.ParseDuration(req.TTL)
+		if err != nil || parsed <= 0 {
+			response.Error(w, r, response.Validation(fmt.Sprintf("ttl must be a positive duration such as 10m (got %q)", req.TTL)))
+			return
+		}
+		ttl = parsed
+	}
+
+	name := chi.URLParam(r, "name")
+	before := h.levelsResponse()
+	if err := h.levels.SetLevelFor(name, req.Level, ttl); err != nil {
+		response.Error(w, r, response.Validation(fmt.Sprintf("level must be one of debug, info, warn, error (got %q)", req.Level)))
+		return
+	}
+
+	h.logger.WithContext(r.Context()).Warn("Log level changed",
+		"logger", name,
+		"level", req.Level,
+		"ttl", ttl.String(),
+	)
+	after := h.levelsResponse()
+	h.recordAudit(r, "admin.log_level.changed", name, before, after)
+	response.Success(w, r, http.StatusOK, after)
+}
+
+// Reset handles DELETE /log-level (reverts a temporary global level now) and
+// DELETE /log-level/{name} (the named logger follows the global level again).
+func (h *LogLevelHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	before := h.levelsResponse()
+	h.levels.ResetLevel(name)
+
+	h.logger.WithContext(r.Context()).Warn("Log level reset", "logger", name)
+	after := h.levelsResponse()
+	h.recordAudit(r, "admin.log_level.reset", name, before, after)
+	response.Success(w, r, http.StatusOK, after)
+}
+
+// recordAudit adds a level change made with the admin token to the audit
+// trail, when there is one. The actor is the principal set by the admin
+// authentication. The change is already applied, so a failure is logged
+// instead of returned.
+func (h *LogLevelHandler) recordAudit(r *http.Request, action, name string, before, after logLevelsResponse) {
+	if h.audit == nil {
+		return
+	}
+
+	resource := "log_level/global"
+	if name != "" {
+		resource = "log_level/" + name
+	}
+	err := h.audit.Record(r.Context(), port.AuditEntry{
+		Action:   action,
+		Resource: resource,
+		Before:   before,
+		After:    after,
+		ClientIP: middleware.GetRealIP(r),
+	})
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to record audit entry",
+			"action", action,
+			"resource", resource,
+			"error", err,
+		)
+	}
+}
+
+// levelsResponse maps the current levels to their JSON representation.
+func (h *LogLevelHandler) levelsResponse() logLevelsResponse {
+	res := logLevelsResponse{Overrides: []logLevelResponse{}}
+	for _, state := range h.levels.Levels() {
+		level := logLevelResponse{
+			Logger:   state.Name,
+			Level:    state.Level,
+			RevertTo: state.RevertTo,
+		}
+		if !state.ExpiresAt.IsZero() {
+			expiresAt := state.ExpiresAt.UTC()
+			level.ExpiresAt = &expiresAt
+		}
+
+		if state.Name == "" {
+			res.Global = level
+		} else {
+			res.Overrides = append(res.Overrides, level)
+		}
+	}
+	return res
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/infrastructure/logging"
+	"github.com/hapkiduki/order-go/pkg/logger"
+	"github.com/hapkiduki/order-go/pkg/logger/loggertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditRecorder is a port.AuditLogger keeping the entries in memory.
+type auditRecorder struct {
+	entries []port.AuditEntry
+}
+
+func (a *auditRecorder) Record(_ context.Context, entry port.AuditEntry) error {
+	a.entries = append(a.entries, entry)
+	return nil
+}
+
+// logLevelRequest sends a request to the log level routes and decodes the
+// levels of a successful response.
+func logLevelRequest(t *testing.T, h http.Handler, method, path, body string) (*httptest.ResponseRecorder, logLevelsResponse) {
+	t.Helper()
+
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	var res struct {
+		Data logLevelsResponse `json:"data"`
+	}
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	}
+	return w, res.Data
+}
+
+func newLogLevelRoutes(t *testing.T) (http.Handler, *logger.Logger, *auditRecorder) {
+	t.Helper()
+
+	rec := loggertest.New()
+	require.NoError(t, rec.Logger().SetLevel("info"))
+	audit := &auditRecorder{}
+	h := NewLogLevelHandler(rec.Logger(), logging.New(rec.Logger()), audit)
+	return h.Routes(), rec.Logger(), audit
+}
+
+func TestLogLevelHandler_RejectsInvalidLevels(t *testing.T) {
+	routes, log, audit := newLogLevelRoutes(t)
+
+	tests := []struct {
+		name string
+		path string
+		body string
+	}{
+		{"empty body", "/", ``},
+		{"no level", "/", `{}`},
+		{"empty level", "/", `{"level":""}`},
+		{"fatal", "/", `{"level":"fatal"}`},
+		{"panic on a named logger", "/orders", `{"level":"panic"}`},
+		{"dpanic", "/", `{"level":"dpanic"}`},
+		{"unknown level", "/", `{"level":"verbose"}`},
+		{"invalid ttl", "/", `{"level":"debug","ttl":"soon"}`},
+		{"negative ttl", "/", `{"level":"debug","ttl":"-1m"}`},
+		{"unknown field", "/", `{"level":"debug","until":"10m"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, _ := logLevelRequest(t, routes, http.MethodPut, tt.path, tt.body)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		})
+	}
+
+	assert.Equal(t, "info", log.Level(), "unchanged")
+	assert.Len(t, log.Levels(), 1)
+	assert.Empty(t, audit.entries)
+}
+
+func TestLogLevelHandler_SetAndReset(t *testing.T) {
+	routes, log, audit := newLogLevelRoutes(t)
+
+	w, levels := logLevelRequest(t, routes, http.MethodPut, "/", `{"level":"debug","ttl":"10m"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "debug", levels.Global.Level)
+	assert.Equal(t, "info", levels.Global.RevertTo)
+	assert.NotNil(t, levels.Global.ExpiresAt)
+
+	w, levels = logLevelRequest(t, routes, http.MethodPut, "/orders", `{"level":"error"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []logLevelResponse{{Logger: "orders", Level: "error"}}, levels.Overrides)
+
+	w, levels = logLevelRequest(t, routes, http.MethodDelete, "/orders", ``)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, levels.Overrides)
+
+	w, levels = logLevelRequest(t, routes, http.MethodDelete, "/", ``)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "info", levels.Global.Level, "the temporary level is reverted")
+	assert.Nil(t, levels.Global.ExpiresAt)
+	assert.Equal(t, "info", log.Level())
+
+	w, levels = logLevelRequest(t, routes, http.MethodGet, "/", ``)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, logLevelResponse{Level: "info"}, levels.Global)
+
+	// Every change is audited with the levels before and after
+	require.Len(t, audit.entries, 4)
+	assert.Equal(t, "admin.log_level.changed", audit.entries[0].Action)
+	assert.Equal(t, "log_level/global", audit.entries[0].Resource)
+	assert.Equal(t, "log_level/orders", audit.entries[1].Resource)
+	assert.Equal(t, "admin.log_level.reset", audit.entries[2].Action)
+	assert.Equal(t, "info", audit.entries[0].Before.(logLevelsResponse).Global.Level)
+	assert.Equal(t, "debug", audit.entries[0].After.(logLevelsResponse).Global.Level)
+}
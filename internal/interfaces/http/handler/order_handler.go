@@ -0,0 +1,336 @@
+// Package handler provides the HTTP handlers (driving adapters) for the REST API.
+// Handlers decode requests, call application services and encode responses
+// using the standard {"success": ..., "data" | "error": ...} envelope.
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/application/service"
+	"github.com/hapkiduki/order-go/internal/domain/order"
+	"github.com/hapkiduki/order-go/internal/interfaces/http/response"
+)
+
+// OrderService defines the use cases the order handler depends on.
+// It is satisfied by *service.OrderService.
+type OrderService interface {
+	CreateOrder(ctx context.Context, in service.CreateOrderInput) (*order.Order, error)
+	GetOrder(ctx context.Context, id string) (*order.Order, error)
+	ListOrders(ctx context.Context, filter port.OrderFilter) ([]*order.Order, int, error)
+	CancelOrder(ctx context.Context, id string) (*order.Order, error)
+	UpdateStatus(ctx context.Context, id string, status order.Status) (*order.Order, error)
+}
+
+// OrderHandler serves the /api/v1/orders endpoints.
+type OrderHandler struct {
+	service        OrderService
+	logger         port.Logger
+	maxRequestSize int64
+}
+
+// NewOrderHandler creates a new OrderHandler.
+//
+// Parameters:
+//   - svc: The order service
+//   - logger: The logger to use
+//   - maxRequestSize: Maximum accepted request body size in bytes
+//
+// Returns:
+//   - *OrderHandler: The handler
+func NewOrderHandler(svc OrderService, logger port.Logger, maxRequestSize int64) *OrderHandler {
+	return &OrderHandler{
+		service:        svc,
+		logger:         logger,
+		maxRequestSize: maxRequestSize,
+	}
+}
+
+// Routes returns a router with all order endpoints.
+// Mount it under the versioned API prefix, e.g. r.Mount("/api/v1/orders", h.Routes()).
+//
+// Returns:
+//   - chi.Router: The order routes
+func (h *OrderHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Post("/", h.Create)
+	r.Get("/", h.List)
+	r.Get("/{orderID}", h.Get)
+	r.Post("/{orderID}/cancel", h.Cancel)
+	r.Patch("/{orderID}/status", h.UpdateStatus)
+	return r
+}
+
+// lineItemRequest is the JSON representation of a line item in requests.
+type lineItemRequest struct {
+	ProductID string `json:"product_id"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice int64  `json:"unit_price"`
+}
+
+// createOrderRequest is the body of POST /orders.
+type createOrderRequest struct {
+	CustomerID string            `json:"customer_id"`
+	Currency   string            `json:"currency"`
+	Lines      []lineItemRequest `json:"lines"`
+}
+
+// updateStatusRequest is the body of PATCH /orders/{orderID}/status.
+type updateStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// lineItemResponse is the JSON representation of a line item in responses.
+type lineItemResponse struct {
+	ProductID string `json:"product_id"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice int64  `json:"unit_price"`
+	Subtotal  int64  `json:"subtotal"`
+}
+
+// orderResponse is the JSON representation of an order.
+type orderResponse struct {
+	ID         string             `json:"id"`
+	CustomerID string             `json:"customer_id"`
+	Status     string             `json:"status"`
+	Currency   string             `json:"currency"`
+	Total      int64              `json:"total"`
+	Lines      []lineItemResponse `json:"lines"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+}
+
+// listOrdersResponse is the JSON representation of a page of orders.
+type listOrdersResponse struct {
+	Orders []orderResponse `json:"orders"`
+	Total  int             `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
+
+// newOrderResponse maps a domain order to its JSON representation.
+func newOrderResponse(o *order.Order) orderResponse {
+	lines := o.Lines()
+	items := make([]lineItemResponse, 0, len(lines))
+	for _, line := range lines {
+		items = append(items, lineItemResponse{
+			ProductID: line.ProductID,
+			Name:      line.Name,
+			Quantity:  line.Quantity,
+			UnitPrice: line.UnitPrice,
+			Subtotal:  line.Subtotal(),
+		})
+	}
+
+	return orderResponse{
+		ID:         o.ID(),
+		CustomerID: o.CustomerID(),
+		Status:     o.Status().String(),
+		Currency:   o.Currency(),
+		Total:      o.Total(),
+		Lines:      items,
+		CreatedAt:  o.CreatedAt(),
+		UpdatedAt:  o.UpdatedAt(),
+	}
+}
+
+// Create handles POST /orders.
+func (h *OrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req createOrderRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	lines := make([]order.LineItem, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		lines = append(lines, order.LineItem{
+			ProductID: line.ProductID,
+			Name:      line.Name,
+			Quantity:  line.Quantity,
+			UnitPrice: line.UnitPrice,
+		})
+	}
+
+	o, err := h.service.CreateOrder(r.Context(), service.CreateOrderInput{
+		CustomerID: req.CustomerID,
+		Currency:   req.Currency,
+		Lines:      lines,
+	})
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/orders/"+o.ID())
+	response.Success(w, r, http.StatusCreated, newOrderResponse(o))
+}
+
+// Get handles GET /orders/{orderID}.
+func (h *OrderHandler) Get(w http.ResponseWriter, r *http.Request) {
+	o, err := h.service.GetOrder(r.Context(), chi.URLParam(r, "orderID"))
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	response.Success(w, r, http.StatusOK, newOrderResponse(o))
+}
+
+// List handles GET /orders with optional customer_id, status, limit and offset query parameters.
+func (h *OrderHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, err := parseIntParam(query.Get("limit"))
+	if err != nil {
+		response.Error(w, r, response.Validation("limit must be an integer"))
+		return
+	}
+	offset, err := parseIntParam(query.Get("offset"))
+	if err != nil {
+		response.Error(w, r, response.Validation("offset must be an integer"))
+		return
+	}
+
+	filter := port.OrderFilter{
+		CustomerID: query.Get("customer_id"),
+		Status:     order.Status(query.Get("status")),
+		Limit:      limit,
+		Offset:     offset,
+	}
+
+	orders, total, err := h.service.ListOrders(r.Context(), filter)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	items := make([]orderResponse, 0, len(orders))
+	for _, o := range orders {
+		items = append(items, newOrderResponse(o))
+	}
+
+	// Echo the page size actually applied by the service
+	if limit <= 0 {
+		limit = service.DefaultListLimit
+	}
+	limit = min(limit, service.MaxListLimit)
+
+	response.Success(w, r, http.StatusOK, listOrdersResponse{
+		Orders: items,
+		Total:  total,
+		Limit:  limit,
+		Offset: max(offset, 0),
+	})
+}
+
+// Cancel handles POST /orders/{orderID}/cancel.
+func (h *OrderHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	o, err := h.service.CancelOrder(r.Context(), chi.URLParam(r, "orderID"))
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	response.Success(w, r, http.StatusOK, newOrderResponse(o))
+}
+
+// UpdateStatus handles PATCH /orders/{orderID}/status.
+func (h *OrderHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	var req updateStatusRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	status, err := order.ParseStatus(req.Status)
+	if err != nil {
+		response.Error(w, r, response.Validation(err.Error()))
+		return
+	}
+
+	o, err := h.service.UpdateStatus(r.Context(), chi.URLParam(r, "orderID"), status)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	response.Success(w, r, http.StatusOK, newOrderResponse(o))
+}
+
+// decode reads a JSON request body into dst, writing a 400 response on failure.
+// Returns false if the request was rejected.
+func (h *OrderHandler) decode(w http.ResponseWriter, r *http.Request, dst any) bool {
+	if h.maxRequestSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.maxRequestSize)
+	}
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.Error(w, r, response.RequestTooLarge())
+			return false
+		}
+		response.Error(w, r, response.InvalidJSON().WithCause(err))
+		return false
+	}
+	return true
+}
+
+// writeServiceError maps domain and application errors to HTTP responses.
+func (h *OrderHandler) writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	appErr := toAppError(err)
+	if appErr.Status >= http.StatusInternalServerError {
+		h.logger.WithContext(r.Context()).Error("Order request failed",
+			"error", err,
+			"path", r.URL.Path,
+		)
+	}
+	response.Error(w, r, appErr)
+}
+
+// toAppError translates order domain errors into API errors.
+func toAppError(err error) *response.AppError {
+	var validationErr *order.ValidationError
+	var transitionErr *order.TransitionError
+
+	switch {
+	case errors.Is(err, order.ErrNotFound):
+		return response.NotFound("Order not found").WithCause(err)
+	case errors.As(err, &transitionErr):
+		return response.Conflict("INVALID_STATUS_TRANSITION", transitionErr.Error()).
+			WithDetails(map[string]any{
+				"from":    transitionErr.From,
+				"to":      transitionErr.To,
+				"allowed": transitionErr.From.NextStatuses(),
+			}).
+			WithCause(err)
+	case errors.As(err, &validationErr):
+		return response.Validation(validationErr.Error()).
+			WithDetails(map[string]string{"field": validationErr.Field}).
+			WithCause(err)
+	case errors.Is(err, order.ErrNotModifiable):
+		return response.Conflict("ORDER_NOT_MODIFIABLE", err.Error()).WithCause(err)
+	case errors.Is(err, order.ErrConcurrentModification):
+		return response.Conflict("CONCURRENT_MODIFICATION",
+			"The order was modified by another request, reload it and try again").WithCause(err)
+	default:
+		return response.AsAppError(err)
+	}
+}
+
+// parseIntParam parses an optional integer query parameter. Empty values return 0.
+func parseIntParam(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hapkiduki/order-go/internal/domain/order"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToAppError_ConcurrentModification(t *testing.T) {
+	err := fmt.Errorf("save order 42: %w", order.ErrConcurrentModification)
+
+	appErr := toAppError(err)
+
+	assert.Equal(t, http.StatusConflict, appErr.Status)
+	assert.Equal(t, "CONCURRENT_MODIFICATION", appErr.Code)
+	assert.ErrorIs(t, appErr, order.ErrConcurrentModification)
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"sync/atomic"
+
+	"github.com/go-chi/cors"
+)
+
+// CORS is a CORS middleware whose allowed origins can be replaced at runtime
+// (e.g., on configuration reload) without rebuilding the router.
+type CORS struct {
+	options cors.Options
+	handler atomic.Pointer[cors.Cors]
+}
+
+// NewCORS creates a CORS middleware.
+//
+// Parameters:
+//   - options: The CORS options; AllowedOrigins can later be changed with SetAllowedOrigins
+//
+// Returns:
+//   - *CORS: The middleware (use its Handler method with r.Use)
+func NewCORS(options cors.Options) *CORS {
+	c := &CORS{options: options}
+	c.SetAllowedOrigins(options.AllowedOrigins)
+	return c
+}
+
+// SetAllowedOrigins replaces the allowed origins.
+// Requests already in flight finish with the previous origins.
+//
+// Parameters:
+//   - origins: The allowed origins ("*" allows all, "https://*.example.com" allows subdomains)
+func (c *CORS) SetAllowedOrigins(origins []string) {
+	options := c.options
+	options.AllowedOrigins = slices.Clone(origins)
+	c.handler.Store(cors.New(options))
+}
+
+// Handler returns the CORS middleware handler.
+//
+// Parameters:
+//   - next: The next handler
+//
+// Returns:
+//   - http.Handler: The handler applying the current CORS options
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.handler.Load().Handler(next).ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/pkg/ctxkeys"
+)
+
+// FlagContext returns a middleware that stores the feature flag evaluation
+// attributes (tenant, client IP and environment) in the request context, so
+// port.FeatureFlags can evaluate flags per request. The user ID is read from
+// the context at evaluation time, once authentication has set it. The tenant
+// ID is also stored under ctxkeys.TenantID, so it is added to logs.
+//
+// Place it after RealIP so the client IP is the real one.
+//
+// Parameters:
+//   - environment: The deployment environment (e.g., "production")
+//   - tenantHeader: The request header carrying the tenant ID (e.g., "X-Tenant-ID")
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware function
+func FlagContext(environment, tenantHeader string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenantID := r.Header.Get(tenantHeader)
+			ctx := r.Context()
+			if tenantID != "" {
+				ctx = ctxkeys.With(ctx, ctxkeys.TenantID, tenantID)
+			}
+
+			ctx = port.WithFlagContext(ctx, port.FlagContext{
+				TenantID:    tenantID,
+				ClientIP:    GetRealIP(r),
+				Environment: environment,
+			})
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
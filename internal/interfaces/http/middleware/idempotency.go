@@ -0,0 +1,255 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/interfaces/http/response"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client-generated idempotency key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader is set on responses replayed from the idempotency store.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength bounds the accepted key size.
+	maxIdempotencyKeyLength = 255
+
+	// idempotencyLockGrace is added to the request deadline when deriving the
+	// lock TTL, so a handler finishing right at its deadline still holds its key.
+	idempotencyLockGrace = 5 * time.Second
+)
+
+// IdempotencyConfig contains Idempotency-Key middleware configuration.
+type IdempotencyConfig struct {
+	// Store persists reservations and completed responses
+	Store port.IdempotencyStore
+
+	// TTL is how long completed responses are replayed
+	// Default: 24 hours
+	TTL time.Duration
+
+	// LockTTL is how long an in-flight request holds its key. It is raised
+	// per request to cover the request deadline (see Timeout), so a retry can
+	// never run while the first request may still be running.
+	// Default: 1 minute
+	LockTTL time.Duration
+
+	// MaxBodyBytes is the maximum request body size that is buffered for fingerprinting
+	// Default: 1MB
+	MaxBodyBytes int64
+
+	// ClientFunc identifies the client so keys from different clients never collide
+	// Default: the authenticated principal (UserIDKey), or the real client IP
+	// for anonymous requests (see idempotencyClient)
+	ClientFunc func(*http.Request) string
+}
+
+// Idempotency returns a middleware that makes POST and PATCH requests carrying an
+// Idempotency-Key header safe to retry.
+//
+// Behavior per key and client:
+//   - First request: processed normally; the response is stored unless it is a 5xx
+//   - Retry with the same body: the stored response is replayed
+//   - Retry while the first request is still in flight: 409 Conflict
+//   - Retry with a different body: 409 Conflict
+//
+// Mount it after the request Timeout middleware: the key is held until the
+// request deadline (plus a grace period) when that is longer than LockTTL.
+// Mount it after authentication too, so keys belong to the principal rather
+// than to the client IP. It panics if config.Store is nil.
+//
+// Parameters:
+//   - config: Idempotency configuration
+//   - logger: The logger to use
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware function
+func Idempotency(config IdempotencyConfig, logger port.Logger) func(http.Handler) http.Handler {
+	if config.Store == nil {
+		panic("middleware: IdempotencyConfig.Store is required (e.g., memory.NewIdempotencyStore())")
+	}
+
+	// Set defaults if not provided
+	if config.TTL == 0 {
+		config.TTL = 24 * time.Hour
+	}
+	if config.LockTTL == 0 {
+		config.LockTTL = time.Minute
+	}
+	if config.MaxBodyBytes == 0 {
+		config.MaxBodyBytes = 1 << 20
+	}
+	if config.ClientFunc == nil {
+		config.ClientFunc = idempotencyClient
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || (r.Method != http.MethodPost && r.Method != http.MethodPatch) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(key) > maxIdempotencyKeyLength {
+				response.Error(w, r, response.Validation("Idempotency-Key must be at most 255 characters"))
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, config.MaxBodyBytes))
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					response.Error(w, r, response.RequestTooLarge())
+					return
+				}
+				response.Error(w, r, response.InvalidJSON().WithCause(err))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := r.Context()
+			storeKey := hashParts(config.ClientFunc(r), key)
+			fingerprint := hashParts(r.Method, r.URL.Path, string(body))
+			log := logger.WithContext(ctx).With("idempotency_key", key)
+
+			existing, acquired, err := config.Store.Reserve(ctx, storeKey, fingerprint, lockTTL(ctx, config.LockTTL))
+			if err != nil {
+				log.Error("Idempotency store unavailable", "error", err)
+				response.Error(w, r, response.Internal().WithCause(err))
+				return
+			}
+
+			if !acquired {
+				switch {
+				case existing.Fingerprint != fingerprint:
+					response.Error(w, r, response.Conflict(response.CodeIdempotencyKeyReused,
+						"Idempotency-Key was already used with a different request"))
+				case !existing.Completed:
+					w.Header().Set("Retry-After", "1")
+					response.Error(w, r, response.Conflict(response.CodeIdempotencyKeyInFlight,
+						"A request with this Idempotency-Key is still being processed"))
+				default:
+					replay(w, existing)
+				}
+				return
+			}
+
+			rec := &recordingWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			completed := false
+			defer func() {
+				// Release the key on 5xx or panic so the client can retry
+				if !completed {
+					if err := config.Store.Release(context.WithoutCancel(ctx), storeKey); err != nil {
+						log.Error("Failed to release idempotency key", "error", err)
+					}
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+
+			if rec.statusCode >= http.StatusInternalServerError {
+				return
+			}
+
+			header := rec.Header().Clone()
+			header.Del(RequestIDHeader)
+
+			if err := config.Store.Complete(context.WithoutCancel(ctx), storeKey, port.IdempotencyRecord{
+				Fingerprint: fingerprint,
+				StatusCode:  rec.statusCode,
+				Header:      header,
+				Body:        rec.body.Bytes(),
+				CreatedAt:   time.Now().UTC(),
+			}, config.TTL); err != nil {
+				log.Error("Failed to store idempotent response", "error", err)
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// lockTTL returns how long a request holds its key: at least until its
+// deadline plus idempotencyLockGrace.
+func lockTTL(ctx context.Context, configured time.Duration) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return max(configured, time.Until(deadline)+idempotencyLockGrace)
+	}
+	return configured
+}
+
+// replay writes a stored response.
+func replay(w http.ResponseWriter, record *port.IdempotencyRecord) {
+	for name, values := range record.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.StatusCode)
+	w.Write(record.Body)
+}
+
+// idempotencyClient is the default IdempotencyConfig.ClientFunc: the
+// authenticated principal, so a retry sent from another IP (mobile handover,
+// NAT or proxy pool) still finds its key. Anonymous requests fall back to the
+// real client IP, and their retries only match when sent from the same IP.
+//
+// Parameters:
+//   - r: The request
+//
+// Returns:
+//   - string: The client identity, prefixed by its kind so a user ID can
+//     never collide with an IP
+func idempotencyClient(r *http.Request) string {
+	if userID := GetUserID(r.Context()); userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + GetRealIP(r)
+}
+
+// hashParts returns the hex SHA-256 of the given parts, separated by NUL bytes.
+func hashParts(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter wraps http.ResponseWriter to capture the status code and body
+// while still streaming the response to the client.
+type recordingWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+// WriteHeader captures the status code.
+func (rw *recordingWriter) WriteHeader(code int) {
+	if !rw.wroteHeader {
+		rw.statusCode = code
+		rw.wroteHeader = true
+		rw.ResponseWriter.WriteHeader(code)
+	}
+}
+
+// Write captures the body.
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"github.com/hapkiduki/order-go/internal/infrastructure/logging"
+	"github.com/hapkiduki/order-go/internal/infrastructure/persistence/memory"
+	"github.com/hapkiduki/order-go/pkg/ctxkeys"
+	"github.com/hapkiduki/order-go/pkg/logger/loggertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// idempotentRequest sends a POST with an Idempotency-Key to handler.
+func idempotentRequest(handler http.Handler, key, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(body))
+	r.Header.Set(IdempotencyKeyHeader, key)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+// countingHandler answers 201 with a body numbering its calls.
+func countingHandler(calls *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"call":%d}`, n)
+	})
+}
+
+func newIdempotency(store port.IdempotencyStore) func(http.Handler) http.Handler {
+	return Idempotency(IdempotencyConfig{Store: store}, logging.New(loggertest.New().Logger()))
+}
+
+func TestIdempotency_ReplaysSameBody(t *testing.T) {
+	var calls atomic.Int32
+	handler := newIdempotency(memory.NewIdempotencyStore())(countingHandler(&calls))
+
+	first := idempotentRequest(handler, "key-1", `{"customer_id":"c1"}`)
+	require.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+
+	second := idempotentRequest(handler, "key-1", `{"customer_id":"c1"}`)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, int32(1), calls.Load())
+
+	// Another key is another request
+	third := idempotentRequest(handler, "key-2", `{"customer_id":"c1"}`)
+	assert.Equal(t, `{"call":2}`, third.Body.String())
+}
+
+func TestIdempotency_ConflictWhileInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := newIdempotency(memory.NewIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- idempotentRequest(handler, "key", `{}`) }()
+	<-started
+
+	retry := idempotentRequest(handler, "key", `{}`)
+	assert.Equal(t, http.StatusConflict, retry.Code)
+	assert.Equal(t, "1", retry.Header().Get("Retry-After"))
+	assert.Contains(t, retry.Body.String(), `"IDEMPOTENCY_KEY_IN_FLIGHT"`)
+
+	close(release)
+	assert.Equal(t, http.StatusCreated, (<-done).Code)
+}
+
+func TestIdempotency_ConflictOnDifferentBody(t *testing.T) {
+	var calls atomic.Int32
+	handler := newIdempotency(memory.NewIdempotencyStore())(countingHandler(&calls))
+
+	require.Equal(t, http.StatusCreated, idempotentRequest(handler, "key", `{"total":1}`).Code)
+
+	w := idempotentRequest(handler, "key", `{"total":2}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"IDEMPOTENCY_KEY_REUSED"`)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestIdempotency_ReleasesKeyOnServerError(t *testing.T) {
+	var calls atomic.Int32
+	handler := newIdempotency(memory.NewIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	assert.Equal(t, http.StatusServiceUnavailable, idempotentRequest(handler, "key", `{}`).Code)
+
+	retry := idempotentRequest(handler, "key", `{}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Empty(t, retry.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestIdempotency_ReleasesKeyOnPanic(t *testing.T) {
+	var calls atomic.Int32
+	handler := newIdempotency(memory.NewIdempotencyStore())(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			panic("boom")
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	assert.PanicsWithValue(t, "boom", func() { idempotentRequest(handler, "key", `{}`) })
+
+	assert.Equal(t, http.StatusCreated, idempotentRequest(handler, "key", `{}`).Code)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestIdempotency_PassThrough(t *testing.T) {
+	var calls atomic.Int32
+	handler := newIdempotency(memory.NewIdempotencyStore())(countingHandler(&calls))
+
+	// No key
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	// Not POST or PATCH
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(IdempotencyKeyHeader, "key")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.Equal(t, int32(3), calls.Load())
+
+	// Oversized key
+	w := idempotentRequest(handler, strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestIdempotency_RetryFromAnotherIP(t *testing.T) {
+	var calls atomic.Int32
+	handler := newIdempotency(memory.NewIdempotencyStore())(countingHandler(&calls))
+
+	send := func(userID, remoteAddr, key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(`{}`))
+		r.RemoteAddr = remoteAddr
+		if userID != "" {
+			r = r.WithContext(ctxkeys.With(r.Context(), UserIDKey, userID))
+		}
+		r.Header.Set(IdempotencyKeyHeader, key)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	// An authenticated client keeps its keys when its IP changes
+	send("user-1", "198.51.100.1:1234", "key-1")
+	w := send("user-1", "203.0.113.7:4321", "key-1")
+	assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, int32(1), calls.Load())
+
+	// Keys are per principal, whatever the IP
+	w = send("user-2", "198.51.100.1:1234", "key-1")
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, int32(2), calls.Load())
+
+	// Anonymous clients are told apart by IP only: the known limitation
+	send("", "198.51.100.1:1234", "key-2")
+	w = send("", "198.51.100.1:1234", "key-2")
+	assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+	w = send("", "203.0.113.7:4321", "key-2")
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+// lockRecorder records the lock TTL of each reservation.
+type lockRecorder struct {
+	port.IdempotencyStore
+	lockTTL time.Duration
+}
+
+func (s *lockRecorder) Reserve(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (*port.IdempotencyRecord, bool, error) {
+	s.lockTTL = lockTTL
+	return s.IdempotencyStore.Reserve(ctx, key, fingerprint, lockTTL)
+}
+
+func TestIdempotency_LockCoversRequestDeadline(t *testing.T) {
+	store := &lockRecorder{IdempotencyStore: memory.NewIdempotencyStore()}
+	handler := Idempotency(IdempotencyConfig{Store: store, LockTTL: time.Second}, logging.New(loggertest.New().Logger()))(okHandler)
+
+	// Without a deadline the configured TTL is used
+	idempotentRequest(handler, "key-1", `{}`)
+	assert.Equal(t, time.Second, store.lockTTL)
+
+	// A request that may run for two minutes holds its key at least as long
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`)).WithContext(ctx)
+	r.Header.Set(IdempotencyKeyHeader, "key-2")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	assert.Greater(t, store.lockTTL, 2*time.Minute)
+}
+
+func TestIdempotency_RequiresStore(t *testing.T) {
+	assert.Panics(t, func() { Idempotency(IdempotencyConfig{}, logging.New(loggertest.New().Logger())) })
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hapkiduki/order-go/internal/application/port"
+)
+
+// Metric names recorded by the HTTP middleware.
+const (
+	// MetricRequestsTotal counts handled requests by method, route and status.
+	MetricRequestsTotal = "http_requests_total"
+
+	// MetricRequestErrorsTotal counts 5xx responses by method, route and status.
+	MetricRequestErrorsTotal = "http_request_errors_total"
+
+	// MetricRequestDuration records request latency in seconds by method, route and status.
+	MetricRequestDuration = "http_request_duration_seconds"
+
+	// MetricRequestsInFlight is the number of requests currently being served.
+	MetricRequestsInFlight = "http_requests_in_flight"
+
+	// MetricRateLimitedTotal counts requests rejected by the rate limiter.
+	MetricRateLimitedTotal = "http_rate_limited_total"
+
+	// MetricPanicsRecoveredTotal counts panics recovered by the Recoverer middleware.
+	MetricPanicsRecoveredTotal = "http_panics_recovered_total"
+)
+
+// unmatchedRoute labels requests that did not match any route,
+// so arbitrary paths cannot explode metric cardinality.
+const unmatchedRoute = "unmatched"
+
+// routeLabel returns the chi route pattern of the request, or unmatchedRoute
+// when no route matched. "/*" is a router mounted at the root (e.g., the API
+// router next to the probes), which only matches what no other route did.
+//
+// Parameters:
+//   - ctx: The request context, once routing has finished
+//
+// Returns:
+//   - string: The route label
+func routeLabel(ctx context.Context) string {
+	rctx := chi.RouteContext(ctx)
+	if rctx == nil {
+		return unmatchedRoute
+	}
+	if pattern := rctx.RoutePattern(); pattern != "" && pattern != "/*" {
+		return pattern
+	}
+	return unmatchedRoute
+}
+
+// Metrics returns a middleware that records RED metrics (rate, errors, duration)
+// for every request, labelled by chi route pattern, method and status.
+// Place it before Recoverer so recovered panics are counted as 500s.
+//
+// Parameters:
+//   - metrics: The metrics recorder
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware function
+func Metrics(metrics port.Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			// Increments commute, so concurrent requests cannot leave a stale value
+			metrics.GaugeAdd(MetricRequestsInFlight, 1, nil)
+			defer metrics.GaugeAdd(MetricRequestsInFlight, -1, nil)
+
+			ww := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(ww, r)
+
+			// The route pattern is only complete once routing has finished
+			route := routeLabel(r.Context())
+
+			tags := map[string]string{
+				"method": r.Method,
+				"route":  route,
+				"status": strconv.Itoa(ww.statusCode),
+			}
+
+			metrics.Counter(MetricRequestsTotal, 1, tags)
+			metrics.Timing(MetricRequestDuration, time.Since(start), tags)
+			if ww.statusCode >= http.StatusInternalServerError {
+				metrics.Counter(MetricRequestErrorsTotal, 1, tags)
+			}
+		})
+	}
+}
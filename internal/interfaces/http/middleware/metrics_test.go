@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hapkiduki/order-go/internal/infrastructure/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gaugeValue returns the value of the unlabelled gauge name.
+func gaugeValue(t *testing.T, p *metrics.Prometheus, name string) float64 {
+	t.Helper()
+
+	families, err := p.Registry().Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == name {
+			require.Len(t, family.GetMetric(), 1)
+			return family.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	t.Fatalf("gauge %s not found", name)
+	return 0
+}
+
+func TestMetrics_InFlight(t *testing.T) {
+	p := metrics.NewPrometheus("test")
+	const requests = 50
+
+	var started sync.WaitGroup
+	started.Add(requests)
+	release := make(chan struct{})
+	handler := Metrics(p)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		started.Done()
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	var done sync.WaitGroup
+	for range requests {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		}()
+	}
+
+	started.Wait()
+	assert.Equal(t, float64(requests), gaugeValue(t, p, "test_"+MetricRequestsInFlight))
+
+	close(release)
+	done.Wait()
+	assert.Zero(t, gaugeValue(t, p, "test_"+MetricRequestsInFlight), "back to zero when idle")
+}
@@ -11,32 +11,38 @@ package middleware
 
 import (
 	"context"
+	"math"
 	"mime"
 	"net"
 	"net/http"
 	"runtime/debug"
+	"strconv"
 	"strings"
-	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hapkiduki/order-go/internal/application/port"
-	"golang.org/x/time/rate"
+	"github.com/hapkiduki/order-go/internal/interfaces/http/response"
+	"github.com/hapkiduki/order-go/pkg/ctxkeys"
 )
 
-// ContextKey is a custom type for context keys to avoid collisions.
-type ContextKey string
+// ContextKey is the type of the context keys set by the middleware.
+// It is the shared key type of package ctxkeys, read by the logger as well.
+type ContextKey = ctxkeys.Key
 
 const (
 	// RequestIDKey is the context key for request IDs.
-	RequestIDKey ContextKey = "request_id"
+	RequestIDKey = ctxkeys.RequestID
 
 	// RequestIDHeader is the header name for request IDs.
 	RequestIDHeader = "X-Request-ID"
 
 	// RealIPKey is the context key for the real client IP.
-	RealIPKey ContextKey = "real_ip"
+	RealIPKey = ctxkeys.ClientIP
+
+	// UserIDKey is the context key for the authenticated principal.
+	UserIDKey = ctxkeys.UserID
 )
 
 // GetRequestID extracts the request ID from the context.
@@ -47,10 +53,18 @@ const (
 // Returns:
 //   - string: The request ID, or empty string if not found
 func GetRequestID(ctx context.Context) string {
-	if id, ok := ctx.Value(RequestIDKey).(string); ok {
-		return id
-	}
-	return ""
+	return ctxkeys.Get(ctx, RequestIDKey)
+}
+
+// GetUserID extracts the authenticated principal from the context.
+//
+// Parameters:
+//   - ctx: The request context
+//
+// Returns:
+//   - string: The principal, or empty string for unauthenticated requests
+func GetUserID(ctx context.Context) string {
+	return ctxkeys.Get(ctx, UserIDKey)
 }
 
 // GetRealIP extracts the real client IP from the context.
@@ -62,7 +76,7 @@ func GetRequestID(ctx context.Context) string {
 // Returns:
 //   - string: The real client IP address
 func GetRealIP(r *http.Request) string {
-	if ip, ok := r.Context().Value(RealIPKey).(string); ok && ip != "" {
+	if ip := ctxkeys.Get(r.Context(), RealIPKey); ip != "" {
 		return ip
 	}
 	// Fallback to RemoteAddr (original behavior)
@@ -87,7 +101,7 @@ func RequestID(next http.Handler) http.Handler {
 		}
 
 		// Set request ID in context and response header
-		ctx := context.WithValue(r.Context(), RequestIDKey, requestID)
+		ctx := ctxkeys.With(r.Context(), RequestIDKey, requestID)
 		w.Header().Set(RequestIDHeader, requestID)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -95,7 +109,8 @@ func RequestID(next http.Handler) http.Handler {
 }
 
 // Logger returns a middleware that logs HTTP requests.
-// It logs request method, path, status, latency, and client IP.
+// It logs request method, path, status and latency, plus the context fields
+// added by logger.WithContext (request ID, client IP, trace ID...).
 //
 // Parameters:
 //   - logger: The logger to use
@@ -103,6 +118,24 @@ func RequestID(next http.Handler) http.Handler {
 // Returns:
 //   - func(http.Handler) http.Handler: The middleware function
 func Logger(logger port.Logger) func(http.Handler) http.Handler {
+	return LoggerWithPolicy(logger, RequestLogPolicy{})
+}
+
+// LoggerWithPolicy returns a middleware that logs the HTTP requests selected
+// by policy. Server errors are logged as errors and slow requests as
+// warnings, whatever their path; other requests are logged as info unless
+// the policy skips them or leaves them out of its sample.
+//
+// Parameters:
+//   - logger: The logger to use
+//   - policy: Which requests to log
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware function
+func LoggerWithPolicy(logger port.Logger, policy RequestLogPolicy) func(http.Handler) http.Handler {
+	sampler := &requestSampler{rate: policy.SampleRate}
+	suppressed := newSuppressedRequests(logger, policy.SummaryInterval)
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
@@ -116,18 +149,40 @@ func Logger(logger port.Logger) func(http.Handler) http.Handler {
 			// Calculate latency
 			latency := time.Since(start)
 
-			// Get request ID from context
-			requestID := GetRequestID(r.Context())
+			serverError := ww.statusCode >= http.StatusInternalServerError
+			slow := policy.SlowThreshold > 0 && latency >= policy.SlowThreshold
+			if !serverError && !slow {
+				if pattern, ok := matchPath(policy.SkipPaths, r.URL.Path); ok {
+					suppressed.add(routeKey(r, pattern))
+					suppressed.report()
+					return
+				}
+				if pattern, ok := matchPath(policy.SamplePaths, r.URL.Path); ok {
+					if route := routeKey(r, pattern); !sampler.sample(route) {
+						suppressed.add(route)
+						suppressed.report()
+						return
+					}
+				}
+			}
+			suppressed.report()
+
+			reqLogger := logger.WithContext(r.Context())
+			log := reqLogger.Info
+			switch {
+			case serverError:
+				log = reqLogger.Error
+			case slow:
+				log = reqLogger.Warn
+			}
 
 			// Log request details
-			logger.Info("HTTP Request",
-				"request_id", requestID,
+			log("HTTP Request",
 				"method", r.Method,
 				"path", r.URL.Path,
 				"query", r.URL.RawQuery,
 				"status", ww.statusCode,
 				"latency_ms", latency.Milliseconds(),
-				"client_ip", GetRealIP(r),
 				"user_agent", r.UserAgent(),
 			)
 		})
@@ -159,32 +214,32 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 }
 
 // Recoverer returns a middleware that recovers from panics.
-// It logs the panic and returns a 500 Internal Server Error.
+// It logs the panic, counts it and returns a 500 Internal Server Error.
 //
 // Parameters:
 //   - logger: The logger to use
+//   - metrics: The metrics recorder (optional, may be nil)
 //
 // Returns:
 //   - func(http.Handler) http.Handler: The middleware function
-func Recoverer(logger port.Logger) func(http.Handler) http.Handler {
+func Recoverer(logger port.Logger, metrics port.Metrics) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if err := recover(); err != nil {
-					requestID := GetRequestID(r.Context())
-
-					logger.Error("Panic recovered",
-						"request_id", requestID,
+					log := logger.WithContext(r.Context())
+					log.Error("Panic recovered",
 						"error", err,
 						"path", r.URL.Path,
 						"stack", string(debug.Stack()),
 					)
 
-					w.Header().Set("Content-Type", "application/json")
-					w.WriteHeader(http.StatusInternalServerError)
-					if _, writeErr := w.Write([]byte(`{"success":false,"error":{"code":"INTERNAL_ERROR","message":"An unexpected error occurred"}}`)); writeErr != nil {
-						logger.Error("Failed to write error response",
-							"request_id", requestID,
+					if metrics != nil {
+						metrics.Counter(MetricPanicsRecoveredTotal, 1, map[string]string{"method": r.Method})
+					}
+
+					if writeErr := response.Error(w, r, response.Internal()); writeErr != nil {
+						log.Error("Failed to write error response",
 							"error", writeErr,
 							"path", r.URL.Path,
 						)
@@ -205,26 +260,26 @@ type RateLimiterConfig struct {
 	// Burst is the maximum burst size
 	Burst int
 
+	// Limits overrides RequestsPerSecond and Burst when set, so they can be
+	// changed at runtime (e.g., on configuration reload). Optional.
+	Limits *RateLimits
+
 	// KeyFunc extracts the key for rate limiting (e.g., client IP)
 	KeyFunc func(*http.Request) string
 
-	// CleanupInterval is how often to clean up inactive limiters
-	// Default: 5 minutes
-	CleanupInterval time.Duration
+	// Store holds the token buckets (required).
+	// Use a shared store (Redis) so limits hold across replicas.
+	Store port.RateLimitStore
 
-	// InactiveTTL is how long a limiter can be inactive before being removed
-	// Default: 10 minutes
-	InactiveTTL time.Duration
-}
+	// Logger reports store failures (optional, may be nil)
+	Logger port.Logger
 
-// limiterEntry stores a rate limiter with its last access time.
-// lastAccess is stored as unix timestamp (int64) for atomic operations.
-type limiterEntry struct {
-	limiter    *rate.Limiter
-	lastAccess int64 // unix timestamp in nanoseconds (atomic)
+	// Metrics records rejected requests (optional, may be nil)
+	Metrics port.Metrics
 }
 
 // DefaultRateLimiterConfig returns the default rate limiter configuration.
+// The Store must still be set by the caller.
 //
 // Returns:
 //   - RateLimiterConfig: Default configuration
@@ -235,14 +290,16 @@ func DefaultRateLimiterConfig() RateLimiterConfig {
 		KeyFunc: func(r *http.Request) string {
 			return GetRealIP(r)
 		},
-		CleanupInterval: 5 * time.Minute,
-		InactiveTTL:     10 * time.Minute,
 	}
 }
 
 // RateLimiter returns a middleware that limits request rate per client.
-// It uses a token bucket algorithm with per-client buckets.
-// The implementation includes automatic cleanup of inactive limiters to prevent memory leaks.
+// It uses a token bucket per client, kept in the configured store.
+// If the store is unavailable the request is allowed (fail open), so a cache
+// outage does not take the API down.
+//
+// It panics if config.Store is nil, so a missing store fails at startup
+// rather than on the first request.
 //
 // Parameters:
 //   - config: Rate limiter configuration
@@ -250,88 +307,40 @@ func DefaultRateLimiterConfig() RateLimiterConfig {
 // Returns:
 //   - func(http.Handler) http.Handler: The middleware function
 func RateLimiter(config RateLimiterConfig) func(http.Handler) http.Handler {
-	// Set defaults if not provided
-	if config.CleanupInterval == 0 {
-		config.CleanupInterval = 5 * time.Minute
-	}
-	if config.InactiveTTL == 0 {
-		config.InactiveTTL = 10 * time.Minute
+	if config.Store == nil {
+		panic("middleware: RateLimiterConfig.Store is required (e.g., memory.NewRateLimitStore(0, 0))")
 	}
 
-	limiters := make(map[string]*limiterEntry)
-	mu := sync.RWMutex{}
-
-	// Context for cleanup goroutine
-	// Note: the cleanup goroutine runs for the lifetime of the middleware instance.
-	// This is intentional - the middleware is typically created once at startup and
-	// lives for the process lifetime. The goroutine will stop when the process exits.
-	// If you need to stop cleanup explicitly (e.g., for testing), consider returning
-	// the cancel function or using a context passed from the application.
-	ctx, cancel := context.WithCancel(context.Background())
-	_ = cancel // Intentionally not called - cleanup runs for process lifetime
-
-	// Start cleanup goroutine
-	// This goroutine will automatically stop when the process exits
-	go func() {
-		ticker := time.NewTicker(config.CleanupInterval)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				cleanupInactiveLimiters(&mu, limiters, config.InactiveTTL)
-			}
-		}
-	}()
-
-	getLimiter := func(key string) *rate.Limiter {
-		now := time.Now().UnixNano()
-
-		mu.RLock()
-		entry, exists := limiters[key]
-		if exists {
-			// Update last access time atomically while holding read lock
-			// This prevents race condition where entry could be deleted between
-			// releasing read lock and acquiring write lock.
-			atomic.StoreInt64(&entry.lastAccess, now)
-		}
-		mu.RUnlock()
-
-		if exists {
-			return entry.limiter
-		}
-
-		mu.Lock()
-		defer mu.Unlock()
-
-		// Double-check after acquiring write lock
-		if entry, exists = limiters[key]; exists {
-			atomic.StoreInt64(&entry.lastAccess, now)
-			return entry.limiter
-		}
-
-		// Create new limiter
-		limiter := rate.NewLimiter(rate.Limit(config.RequestsPerSecond), config.Burst)
-		limiters[key] = &limiterEntry{
-			limiter:    limiter,
-			lastAccess: now,
-		}
+	// Set defaults if not provided
+	if config.KeyFunc == nil {
+		config.KeyFunc = GetRealIP
+	}
 
-		return limiter
+	if config.Limits == nil {
+		config.Limits = NewRateLimits(config.RequestsPerSecond, config.Burst)
 	}
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			key := config.KeyFunc(r)
-			limiter := getLimiter(key)
 
-			if !limiter.Allow() {
-				w.Header().Set("Content-Type", "application/json")
-				w.Header().Set("Retry-After", "1")
-				w.WriteHeader(http.StatusTooManyRequests)
-				if _, err := w.Write([]byte(`{"success":false,"error":{"code":"RATE_LIMITED","message":"Too many requests, please try again later"}}`)); err != nil {
+			result, err := config.Store.Allow(r.Context(), key, config.Limits.Get())
+			if err != nil {
+				if config.Logger != nil {
+					config.Logger.WithContext(r.Context()).Warn("Rate limiter store unavailable, allowing request",
+						"error", err,
+					)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !result.Allowed {
+				if config.Metrics != nil {
+					config.Metrics.Counter(MetricRateLimitedTotal, 1, map[string]string{"method": r.Method})
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(result.RetryAfter)))
+				if err := response.Error(w, r, response.RateLimited()); err != nil {
 					// Log write error if logger is available (could be added as parameter)
 					// For now, we silently ignore as response writer errors are typically
 					// connection issues that can't be recovered
@@ -344,50 +353,125 @@ func RateLimiter(config RateLimiterConfig) func(http.Handler) http.Handler {
 	}
 }
 
-// cleanupInactiveLimiters removes limiters that haven't been accessed within the TTL period.
-func cleanupInactiveLimiters(mu *sync.RWMutex, limiters map[string]*limiterEntry, ttl time.Duration) {
-	now := time.Now().UnixNano()
-	cutoff := now - ttl.Nanoseconds()
+// RateLimits holds the rate limiter values. Both values are swapped together,
+// so a request never sees the rate of one update and the burst of another.
+type RateLimits struct {
+	limit atomic.Pointer[port.RateLimit]
+}
 
-	mu.Lock()
-	defer mu.Unlock()
+// NewRateLimits creates rate limiter values that can be updated at runtime.
+//
+// Parameters:
+//   - requestsPerSecond: The number of requests allowed per second
+//   - burst: The maximum burst size
+//
+// Returns:
+//   - *RateLimits: The rate limiter values
+func NewRateLimits(requestsPerSecond float64, burst int) *RateLimits {
+	l := &RateLimits{}
+	l.Set(requestsPerSecond, burst)
+	return l
+}
 
-	for key, entry := range limiters {
-		// read lastAccess atomically
-		lastAccess := atomic.LoadInt64(&entry.lastAccess)
-		if lastAccess < cutoff {
-			delete(limiters, key)
-		}
+// Set replaces the rate limiter values. Existing buckets pick up the new
+// values on their next request.
+//
+// Parameters:
+//   - requestsPerSecond: The number of requests allowed per second
+//   - burst: The maximum burst size
+func (l *RateLimits) Set(requestsPerSecond float64, burst int) {
+	l.limit.Store(&port.RateLimit{Rate: requestsPerSecond, Burst: burst})
+}
+
+// Get returns the current rate limiter values.
+//
+// Returns:
+//   - port.RateLimit: The current rate and burst
+func (l *RateLimits) Get() port.RateLimit {
+	return *l.limit.Load()
+}
+
+// retryAfterSeconds rounds a retry delay up to whole seconds (at least 1)
+// for the Retry-After header.
+func retryAfterSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
+
+// SecureHeadersConfig contains the security header values.
+// An empty value disables the header.
+type SecureHeadersConfig struct {
+	// ContentTypeOptions is the X-Content-Type-Options header (prevents MIME type sniffing)
+	ContentTypeOptions string
+
+	// FrameOptions is the X-Frame-Options header (prevents clickjacking)
+	FrameOptions string
+
+	// ContentSecurityPolicy is the Content-Security-Policy header
+	ContentSecurityPolicy string
+
+	// ReferrerPolicy is the Referrer-Policy header
+	ReferrerPolicy string
+
+	// StrictTransportSecurity is the Strict-Transport-Security header (HTTPS only)
+	StrictTransportSecurity string
+}
+
+// DefaultSecureHeadersConfig returns the default security headers.
+//
+// Returns:
+//   - SecureHeadersConfig: Default configuration
+func DefaultSecureHeadersConfig() SecureHeadersConfig {
+	return SecureHeadersConfig{
+		ContentTypeOptions:      "nosniff",
+		FrameOptions:            "DENY",
+		ContentSecurityPolicy:   "default-src 'none'; frame-ancestors 'none'",
+		ReferrerPolicy:          "strict-origin-when-cross-origin",
+		StrictTransportSecurity: "max-age=31536000; includeSubDomains",
 	}
 }
 
-// SecureHeaders returns a middleware that adds security headers.
+// SecureHeaders returns a middleware that adds the default security headers.
 //
 // Returns:
 //   - func(http.Handler) http.Handler: The middleware function
 func SecureHeaders(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Prevent MIME type sniffing
-		w.Header().Set("X-Content-Type-Options", "nosniff")
-
-		// Prevent clickjacking
-		w.Header().Set("X-Frame-Options", "DENY")
-
-		// Enable XSS filter
+	return SecureHeadersWithConfig(DefaultSecureHeadersConfig())(next)
+}
 
-		// Strict Transport Security (if using HTTPS)
-		if r.TLS != nil {
-			w.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+// SecureHeadersWithConfig returns a middleware that adds the configured security headers.
+//
+// Parameters:
+//   - config: Security header values
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware function
+func SecureHeadersWithConfig(config SecureHeadersConfig) func(http.Handler) http.Handler {
+	headers := make(map[string]string)
+	for name, value := range map[string]string{
+		"X-Content-Type-Options":  config.ContentTypeOptions,
+		"X-Frame-Options":         config.FrameOptions,
+		"Content-Security-Policy": config.ContentSecurityPolicy,
+		"Referrer-Policy":         config.ReferrerPolicy,
+	} {
+		if value != "" {
+			headers[name] = value
 		}
+	}
 
-		// Content Security Policy
-		w.Header().Set("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for name, value := range headers {
+				w.Header().Set(name, value)
+			}
 
-		// Referrer Policy
-		w.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
+			// Strict Transport Security (if using HTTPS)
+			if r.TLS != nil && config.StrictTransportSecurity != "" {
+				w.Header().Set("Strict-Transport-Security", config.StrictTransportSecurity)
+			}
 
-		next.ServeHTTP(w, r)
-	})
+			next.ServeHTTP(w, r)
+		})
+	}
 }
 
 // APIVersion returns a middleware that adds API version header.
@@ -421,9 +505,7 @@ func ContentTypeJSON(next http.Handler) http.Handler {
 				// Parse media type to handle charset parameters
 				mediaType, _, err := mime.ParseMediaType(contentType)
 				if err != nil || mediaType != "application/json" {
-					w.Header().Set("Content-Type", "application/json")
-					w.WriteHeader(http.StatusUnsupportedMediaType)
-					if _, err := w.Write([]byte(`{"success":false,"error":{"code":"UNSUPPORTED_MEDIA_TYPE","message":"Content-Type must be application/json"}}`)); err != nil {
+					if err := response.Error(w, r, response.UnsupportedMediaType()); err != nil {
 						// Optionally log the error, if a logger is available
 						// For now, we just ignore it as there's nothing we can do
 					}
@@ -465,11 +547,12 @@ func RealIP(next http.Handler) http.Handler {
 			realIP = strings.TrimSpace(xri)
 		}
 
-		// Validate and store in context instead of modifying RemoteAddr
-		if realIP != "" && net.ParseIP(realIP) != nil {
-			ctx := context.WithValue(r.Context(), RealIPKey, realIP)
-			r = r.WithContext(ctx)
+		// Validate and store in context instead of modifying RemoteAddr.
+		// The connection address is stored otherwise, so logs always carry it.
+		if realIP == "" || net.ParseIP(realIP) == nil {
+			realIP = GetRealIP(r)
 		}
+		r = r.WithContext(ctxkeys.With(r.Context(), RealIPKey, realIP))
 
 		next.ServeHTTP(w, r)
 	})
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hapkiduki/order-go/internal/infrastructure/logging"
+	"github.com/hapkiduki/order-go/internal/infrastructure/persistence/memory"
+	"github.com/hapkiduki/order-go/pkg/logger/loggertest"
+	"github.com/stretchr/testify/assert"
+)
+
+// okHandler answers 200 OK.
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func TestRecoverer(t *testing.T) {
+	rec := loggertest.New()
+	panicking := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+	handler := RequestID(Recoverer(logging.New(rec.Logger()), nil)(panicking))
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)
+	r.Header.Set(RequestIDHeader, "req-1")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), `"req-1"`)
+
+	entry := rec.RequireEntry(t, "error", "Panic recovered")
+	entry.AssertField(t, "request_id", "req-1")
+	entry.AssertField(t, "error", "boom")
+	entry.AssertField(t, "path", "/api/v1/orders")
+	entry.AssertHasField(t, "stack")
+	rec.AssertNoEntry(t, "error", "Failed to write error response")
+}
+
+func TestRateLimiter_RequiresStore(t *testing.T) {
+	assert.PanicsWithValue(t,
+		"middleware: RateLimiterConfig.Store is required (e.g., memory.NewRateLimitStore(0, 0))",
+		func() { RateLimiter(DefaultRateLimiterConfig()) },
+	)
+}
+
+func TestRateLimiter(t *testing.T) {
+	store := memory.NewRateLimitStore(time.Minute, time.Minute)
+	t.Cleanup(store.Close)
+
+	config := DefaultRateLimiterConfig()
+	config.RequestsPerSecond = 1
+	config.Burst = 2
+	config.Store = store
+	handler := RateLimiter(config)(okHandler)
+
+	request := func(remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, request("192.0.2.1:1234").Code)
+	assert.Equal(t, http.StatusOK, request("192.0.2.1:1234").Code)
+
+	w := request("192.0.2.1:1234")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"RATE_LIMITED"`)
+
+	// Other clients have their own bucket
+	assert.Equal(t, http.StatusOK, request("192.0.2.2:1234").Code)
+}
+
+func TestRouteLabel(t *testing.T) {
+	// The layout of main.go: probes on the root router, the API mounted at "/"
+	var route string
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+			route = routeLabel(r.Context())
+		})
+	})
+	r.Get("/health", okHandler)
+	api := chi.NewRouter()
+	api.Get("/api/v1/orders/{orderID}", okHandler)
+	r.Mount("/", api)
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{http.MethodGet, "/health", "/health"},
+		{http.MethodGet, "/api/v1/orders/42", "/api/v1/orders/{orderID}"},
+		{http.MethodGet, "/unknown", unmatchedRoute},
+		{http.MethodGet, "/", unmatchedRoute},
+		{http.MethodPost, "/health", unmatchedRoute},
+	}
+	for _, tt := range tests {
+		route = ""
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+		assert.Equal(t, tt.want, route, "%s %s", tt.method, tt.path)
+	}
+}
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+)
+
+// RequestLogPolicy decides which requests middleware.LoggerWithPolicy logs.
+// Server errors (5xx) and slow requests are always logged (the logger never
+// samples errors, but may sample the slow-request warnings); the zero value
+// logs every request.
+type RequestLogPolicy struct {
+	// SkipPaths are not logged (e.g., "/health"). A trailing "*" matches a prefix.
+	SkipPaths []string
+
+	// SamplePaths are logged at SampleRate (e.g., hot GET endpoints).
+	// A trailing "*" matches a prefix.
+	SamplePaths []string
+
+	// SampleRate is the fraction of SamplePaths requests logged (0.0 - 1.0),
+	// counted per route: /orders/{orderID} is sampled as one route
+	SampleRate float64
+
+	// SlowThreshold always logs requests taking at least this long, as
+	// warnings (0 disables)
+	SlowThreshold time.Duration
+
+	// SummaryInterval is how often the requests not logged are reported with
+	// a "Similar entries suppressed" entry per route (0 disables the summary)
+	SummaryInterval time.Duration
+}
+
+// matchPath returns the first of patterns matching path.
+func matchPath(patterns []string, path string) (string, bool) {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return pattern, true
+			}
+		} else if path == pattern {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// routeKey returns the key requests are sampled and summarized by: the chi
+// route pattern, so every /orders/{orderID} request shares one counter, or
+// the matched policy pattern for requests no route matched. Raw paths are
+// never used, as they would grow the counters without bound.
+func routeKey(r *http.Request, pattern string) string {
+	if route := routeLabel(r.Context()); route != unmatchedRoute {
+		return route
+	}
+	return pattern
+}
+
+// requestSampler logs a deterministic fraction of the requests of each route:
+// with a rate of 0.1, one request in ten.
+type requestSampler struct {
+	rate   float64
+	counts sync.Map // route -> *atomic.Uint64
+}
+
+// sample reports whether this request of route should be logged.
+func (s *requestSampler) sample(route string) bool {
+	if s.rate >= 1 {
+		return true
+	}
+	if s.rate <= 0 {
+		return false
+	}
+
+	counter, _ := s.counts.LoadOrStore(route, new(atomic.Uint64))
+	n := float64(counter.(*atomic.Uint64).Add(1))
+
+	// Log when the running total of rate crosses an integer
+	return math.Floor(n*s.rate) != math.Floor((n-1)*s.rate)
+}
+
+// suppressedRequests counts the requests not logged and reports them
+// periodically. Reports are written while serving requests, so no goroutine
+// is needed.
+type suppressedRequests struct {
+	logger   port.Logger
+	interval time.Duration
+
+	mu         sync.Mutex
+	counts     map[string]int // route -> requests not logged
+	nextReport time.Time
+}
+
+// newSuppressedRequests creates the summary, or returns nil when interval is 0.
+func newSuppressedRequests(logger port.Logger, interval time.Duration) *suppressedRequests {
+	if interval <= 0 {
+		return nil
+	}
+	return &suppressedRequests{
+		logger:     logger,
+		interval:   interval,
+		counts:     map[string]int{},
+		nextReport: time.Now().Add(interval),
+	}
+}
+
+// add counts a request of route that was not logged. It is nil-safe.
+func (s *suppressedRequests) add(route string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.counts[route]++
+	s.mu.Unlock()
+}
+
+// report logs the counts once the interval is over. It is nil-safe.
+func (s *suppressedRequests) report() {
+	if s == nil {
+		return
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	if now.Before(s.nextReport) || len(s.counts) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	counts := s.counts
+	s.counts = map[string]int{}
+	s.nextReport = now.Add(s.interval)
+	s.mu.Unlock()
+
+	for route, n := range counts {
+		s.logger.Info("Similar entries suppressed",
+			"suppressed_message", "HTTP Request",
+			"route", route,
+			"suppressed", n,
+			"interval", s.interval.String(),
+		)
+	}
+}
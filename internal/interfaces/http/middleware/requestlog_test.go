@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/hapkiduki/order-go/internal/infrastructure/logging"
+	"github.com/hapkiduki/order-go/pkg/logger/loggertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerWithPolicy_SamplesByRoute(t *testing.T) {
+	rec := loggertest.New()
+	policy := RequestLogPolicy{
+		SamplePaths:     []string{"/api/v1/orders/*"},
+		SampleRate:      0.1,
+		SummaryInterval: 50 * time.Millisecond,
+	}
+	r := chi.NewRouter()
+	r.Use(LoggerWithPolicy(logging.New(rec.Logger()), policy))
+	r.Get("/api/v1/orders/{orderID}", okHandler)
+
+	get := func(id int) {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/orders/%d", id), nil))
+	}
+
+	// Every request has its own path, but they all share one route
+	for id := 1; id <= 19; id++ {
+		get(id)
+	}
+	logged := rec.Filter("info", "HTTP Request")
+	require.Len(t, logged, 1, "one request in ten")
+	logged[0].AssertField(t, "path", "/api/v1/orders/10")
+	assert.Empty(t, rec.Filter("info", "Similar entries suppressed"), "before the interval is over")
+
+	time.Sleep(policy.SummaryInterval)
+	get(20)
+	assert.Len(t, rec.Filter("info", "HTTP Request"), 2)
+
+	summaries := rec.Filter("info", "Similar entries suppressed")
+	require.Len(t, summaries, 1, "one summary for the route")
+	summaries[0].AssertField(t, "route", "/api/v1/orders/{orderID}")
+	summaries[0].AssertField(t, "suppressed", 18)
+}
+
+func TestLoggerWithPolicy_UnmatchedRoutes(t *testing.T) {
+	rec := loggertest.New()
+	policy := RequestLogPolicy{
+		SkipPaths:       []string{"/static/*"},
+		SummaryInterval: time.Nanosecond,
+	}
+	handler := LoggerWithPolicy(logging.New(rec.Logger()), policy)(okHandler)
+
+	// Without a chi route, requests are counted under the policy pattern
+	for _, path := range []string{"/static/a.js", "/static/b.js", "/static/c.css"} {
+		time.Sleep(time.Millisecond)
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Empty(t, rec.Filter("info", "HTTP Request"))
+	summaries := rec.Filter("info", "Similar entries suppressed")
+	require.Len(t, summaries, 3, "a summary per request with a 1ns interval")
+	for _, summary := range summaries {
+		summary.AssertField(t, "route", "/static/*")
+	}
+}
+
+func TestLoggerWithPolicy_AlwaysLogsErrors(t *testing.T) {
+	rec := loggertest.New()
+	failing := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	handler := LoggerWithPolicy(logging.New(rec.Logger()), RequestLogPolicy{SkipPaths: []string{"/health"}})(failing)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	rec.RequireEntry(t, "error", "HTTP Request").AssertField(t, "status", http.StatusBadGateway)
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hapkiduki/order-go/internal/application/port"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// W3C trace context headers.
+const (
+	// TraceparentHeader carries the trace ID, parent span ID and sampling flag.
+	TraceparentHeader = "traceparent"
+
+	// TracestateHeader carries vendor-specific trace data.
+	TracestateHeader = "tracestate"
+)
+
+// serverSpanStarter is implemented by tracers that can start spans of kind server.
+type serverSpanStarter interface {
+	StartServerSpan(ctx context.Context, operationName string) (context.Context, port.Span)
+}
+
+// spanRenamer is implemented by spans that can be renamed once the route is known.
+type spanRenamer interface {
+	SetName(name string)
+}
+
+// Tracing returns a middleware that starts a span for every request.
+//
+// The incoming traceparent/tracestate headers are extracted with the global
+// OpenTelemetry propagator, so the span joins the caller's trace; the resulting
+// trace context is injected into the response headers. Place it before Logger so
+// request logs carry trace_id and span_id, and before Recoverer so recovered
+// panics are recorded as errors.
+//
+// Parameters:
+//   - tracer: The tracer used to start request spans
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware function
+func Tracing(tracer port.Tracer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			propagator := otel.GetTextMapPropagator()
+			ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			name := r.Method + " " + r.URL.Path
+			var span port.Span
+			if starter, ok := tracer.(serverSpanStarter); ok {
+				ctx, span = starter.StartServerSpan(ctx, name)
+			} else {
+				ctx, span = tracer.StartSpan(ctx, name)
+			}
+			defer span.End()
+
+			span.SetAttribute("http.request.method", r.Method)
+			span.SetAttribute("url.path", r.URL.Path)
+			span.SetAttribute("client.address", GetRealIP(r))
+			span.SetAttribute("user_agent.original", r.UserAgent())
+			if requestID := GetRequestID(ctx); requestID != "" {
+				span.SetAttribute("request.id", requestID)
+			}
+
+			propagator.Inject(ctx, propagation.HeaderCarrier(w.Header()))
+
+			ww := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			// The route pattern is only complete once routing has finished
+			route := routeLabel(ctx)
+			if renamer, ok := span.(spanRenamer); ok {
+				renamer.SetName(r.Method + " " + route)
+			}
+
+			span.SetAttribute("http.route", route)
+			span.SetAttribute("http.response.status_code", ww.statusCode)
+			if ww.statusCode >= http.StatusInternalServerError {
+				span.SetError(errorStatus(ww.statusCode))
+			}
+		})
+	}
+}
+
+// GetTraceID extracts the trace ID of the current span from the context.
+//
+// Parameters:
+//   - ctx: The request context
+//
+// Returns:
+//   - string: The hex trace ID, or empty string if there is no valid span
+func GetTraceID(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
+
+// GetSpanID extracts the ID of the current span from the context.
+//
+// Parameters:
+//   - ctx: The request context
+//
+// Returns:
+//   - string: The hex span ID, or empty string if there is no valid span
+func GetSpanID(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.HasSpanID() {
+		return sc.SpanID().String()
+	}
+	return ""
+}
+
+// errorStatus is an error describing a 5xx response.
+type errorStatus int
+
+// Error implements the error interface.
+func (e errorStatus) Error() string {
+	return http.StatusText(int(e))
+}
@@ -0,0 +1,181 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error codes returned to API clients in the "error.code" field.
+// Codes are part of the public API contract: never rename them.
+const (
+	CodeBadRequest             = "BAD_REQUEST"
+	CodeValidation             = "VALIDATION_ERROR"
+	CodeInvalidJSON            = "INVALID_JSON"
+	CodeUnauthorized           = "UNAUTHORIZED"
+	CodeForbidden              = "FORBIDDEN"
+	CodeNotFound               = "NOT_FOUND"
+	CodeMethodNotAllowed       = "METHOD_NOT_ALLOWED"
+	CodeConflict               = "CONFLICT"
+	CodeIdempotencyKeyInFlight = "IDEMPOTENCY_KEY_IN_FLIGHT"
+	CodeIdempotencyKeyReused   = "IDEMPOTENCY_KEY_REUSED"
+	CodeRequestTooLarge        = "REQUEST_TOO_LARGE"
+	CodeUnsupportedMediaType   = "UNSUPPORTED_MEDIA_TYPE"
+	CodeRateLimited            = "RATE_LIMITED"
+	CodeInternal               = "INTERNAL_ERROR"
+	CodeNotReady               = "NOT_READY"
+)
+
+// AppError is an error that knows how it should be presented to API clients.
+// The Cause is kept for logging and errors.Is/As but is never sent to clients.
+type AppError struct {
+	// Code is the machine-readable error code (e.g., "NOT_FOUND")
+	Code string
+
+	// Message is the human-readable error message
+	Message string
+
+	// Status is the HTTP status code
+	Status int
+
+	// Details contains optional structured information (e.g., invalid fields)
+	Details any
+
+	// Cause is the underlying error, if any
+	Cause error
+}
+
+// NewAppError creates a new AppError.
+//
+// Parameters:
+//   - status: HTTP status code
+//   - code: Machine-readable error code
+//   - message: Human-readable message
+//
+// Returns:
+//   - *AppError: The error
+func NewAppError(status int, code, message string) *AppError {
+	return &AppError{
+		Code:    code,
+		Message: message,
+		Status:  status,
+	}
+}
+
+// Error implements the error interface.
+func (e *AppError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap returns the underlying cause.
+func (e *AppError) Unwrap() error {
+	return e.Cause
+}
+
+// WithDetails returns a copy of the error with the given details.
+//
+// Parameters:
+//   - details: Structured details to include in the response
+//
+// Returns:
+//   - *AppError: A copy of the error with details
+func (e *AppError) WithDetails(details any) *AppError {
+	clone := *e
+	clone.Details = details
+	return &clone
+}
+
+// WithCause returns a copy of the error wrapping cause.
+//
+// Parameters:
+//   - cause: The underlying error
+//
+// Returns:
+//   - *AppError: A copy of the error with the cause
+func (e *AppError) WithCause(cause error) *AppError {
+	clone := *e
+	clone.Cause = cause
+	return &clone
+}
+
+// AsAppError converts any error into an *AppError.
+// Errors that are not (and do not wrap) an *AppError become a generic 500 with err as the cause.
+//
+// Parameters:
+//   - err: The error to convert
+//
+// Returns:
+//   - *AppError: The application error
+func AsAppError(err error) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Internal().WithCause(err)
+}
+
+// BadRequest returns a 400 error with the given message.
+func BadRequest(message string) *AppError {
+	return NewAppError(http.StatusBadRequest, CodeBadRequest, message)
+}
+
+// Validation returns a 400 validation error with the given message.
+func Validation(message string) *AppError {
+	return NewAppError(http.StatusBadRequest, CodeValidation, message)
+}
+
+// InvalidJSON returns a 400 error for malformed request bodies.
+func InvalidJSON() *AppError {
+	return NewAppError(http.StatusBadRequest, CodeInvalidJSON, "Request body must be valid JSON")
+}
+
+// Unauthorized returns a 401 error for missing or invalid credentials.
+func Unauthorized() *AppError {
+	return NewAppError(http.StatusUnauthorized, CodeUnauthorized, "Missing or invalid credentials")
+}
+
+// NotFound returns a 404 error with the given message.
+func NotFound(message string) *AppError {
+	return NewAppError(http.StatusNotFound, CodeNotFound, message)
+}
+
+// MethodNotAllowed returns a 405 error.
+func MethodNotAllowed() *AppError {
+	return NewAppError(http.StatusMethodNotAllowed, CodeMethodNotAllowed,
+		"The requested method is not allowed for this resource")
+}
+
+// Conflict returns a 409 error with the given code and message.
+func Conflict(code, message string) *AppError {
+	return NewAppError(http.StatusConflict, code, message)
+}
+
+// RequestTooLarge returns a 413 error.
+func RequestTooLarge() *AppError {
+	return NewAppError(http.StatusRequestEntityTooLarge, CodeRequestTooLarge, "Request body is too large")
+}
+
+// UnsupportedMediaType returns a 415 error for non-JSON request bodies.
+func UnsupportedMediaType() *AppError {
+	return NewAppError(http.StatusUnsupportedMediaType, CodeUnsupportedMediaType,
+		"Content-Type must be application/json")
+}
+
+// RateLimited returns a 429 error.
+func RateLimited() *AppError {
+	return NewAppError(http.StatusTooManyRequests, CodeRateLimited, "Too many requests, please try again later")
+}
+
+// Internal returns a generic 500 error. The message never reveals internals.
+func Internal() *AppError {
+	return NewAppError(http.StatusInternalServerError, CodeInternal, "An unexpected error occurred")
+}
+
+// NotReady returns a 503 error used by the readiness probe.
+func NotReady() *AppError {
+	return NewAppError(http.StatusServiceUnavailable, CodeNotReady,
+		"One or more critical dependencies are unavailable")
+}
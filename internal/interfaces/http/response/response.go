@@ -0,0 +1,138 @@
+// Package response provides helpers to write JSON API responses using the
+// standard envelope shared by all handlers and middleware:
+//
+//	{"success": true,  "data": {...}, "request_id": "..."}
+//	{"success": false, "error": {"code": "...", "message": "...", "details": ...}, "request_id": "..."}
+//
+// Every error response goes through an *AppError so clients see a single error contract.
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hapkiduki/order-go/pkg/ctxkeys"
+)
+
+// requestIDHeader is the response header set by middleware.RequestID.
+// It is read here instead of importing middleware, which itself uses this package.
+const requestIDHeader = "X-Request-ID"
+
+// Envelope is the top-level JSON structure of every API response.
+type Envelope struct {
+	// Success reports whether the request succeeded
+	Success bool `json:"success"`
+
+	// Data is the response payload (success responses and some error responses)
+	Data any `json:"data,omitempty"`
+
+	// Error describes the failure (error responses only)
+	Error *ErrorBody `json:"error,omitempty"`
+
+	// RequestID correlates the response with server logs
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// ErrorBody is the "error" member of the envelope.
+type ErrorBody struct {
+	// Code is the machine-readable error code
+	Code string `json:"code"`
+
+	// Message is the human-readable error message
+	Message string `json:"message"`
+
+	// Details contains optional structured information
+	Details any `json:"details,omitempty"`
+}
+
+// JSON writes v as a JSON response with the given status code.
+//
+// Parameters:
+//   - w: The response writer
+//   - status: HTTP status code
+//   - v: The value to encode
+//
+// Returns:
+//   - error: Any error encoding or writing the response
+func JSON(w http.ResponseWriter, status int, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, err = w.Write(append(body, '\n'))
+	return err
+}
+
+// Success writes a success envelope with the given data.
+//
+// Parameters:
+//   - w: The response writer
+//   - r: The request being answered
+//   - status: HTTP status code (e.g., http.StatusOK, http.StatusCreated)
+//   - data: The response payload
+//
+// Returns:
+//   - error: Any error writing the response
+func Success(w http.ResponseWriter, r *http.Request, status int, data any) error {
+	return JSON(w, status, Envelope{
+		Success:   true,
+		Data:      data,
+		RequestID: requestID(w, r),
+	})
+}
+
+// Error writes an error envelope. Errors that are not *AppError are
+// reported as a generic 500 so internal details never reach clients.
+//
+// Parameters:
+//   - w: The response writer
+//   - r: The request being answered
+//   - err: The error to report
+//
+// Returns:
+//   - error: Any error writing the response
+func Error(w http.ResponseWriter, r *http.Request, err error) error {
+	return ErrorWithData(w, r, err, nil)
+}
+
+// ErrorWithData writes an error envelope that also carries a data payload
+// (e.g., the readiness report alongside a 503).
+//
+// Parameters:
+//   - w: The response writer
+//   - r: The request being answered
+//   - err: The error to report
+//   - data: Additional payload, omitted if nil
+//
+// Returns:
+//   - error: Any error writing the response
+func ErrorWithData(w http.ResponseWriter, r *http.Request, err error, data any) error {
+	appErr := AsAppError(err)
+
+	return JSON(w, appErr.Status, Envelope{
+		Success: false,
+		Data:    data,
+		Error: &ErrorBody{
+			Code:    appErr.Code,
+			Message: appErr.Message,
+			Details: appErr.Details,
+		},
+		RequestID: requestID(w, r),
+	})
+}
+
+// requestID returns the request ID set by middleware.RequestID in the context,
+// falling back to the response header and then to the ID propagated by an
+// upstream gateway.
+func requestID(w http.ResponseWriter, r *http.Request) string {
+	if id := ctxkeys.Get(r.Context(), ctxkeys.RequestID); id != "" {
+		return id
+	}
+	if id := w.Header().Get(requestIDHeader); id != "" {
+		return id
+	}
+	return r.Header.Get(requestIDHeader)
+}
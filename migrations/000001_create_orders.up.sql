@@ -0,0 +1,13 @@
+-- Orders aggregate root
+CREATE TABLE IF NOT EXISTS orders (
+    id          UUID        PRIMARY KEY,
+    customer_id TEXT        NOT NULL,
+    currency    CHAR(3)     NOT NULL,
+    status      TEXT        NOT NULL CHECK (status IN ('pending', 'confirmed', 'paid', 'shipped', 'delivered', 'cancelled', 'refunded')),
+    created_at  TIMESTAMPTZ NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_customer_created ON orders (customer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_orders_status ON orders (status);
+CREATE INDEX IF NOT EXISTS idx_orders_created ON orders (created_at DESC);
@@ -0,0 +1,10 @@
+-- Order line items, owned by the orders aggregate
+CREATE TABLE IF NOT EXISTS order_lines (
+    order_id   UUID    NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    line_no    INTEGER NOT NULL,
+    product_id TEXT    NOT NULL,
+    name       TEXT    NOT NULL DEFAULT '',
+    quantity   INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price BIGINT  NOT NULL CHECK (unit_price >= 0),
+    PRIMARY KEY (order_id, line_no)
+);
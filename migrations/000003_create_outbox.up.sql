@@ -0,0 +1,19 @@
+-- Transactional outbox: domain events written in the same transaction as the
+-- aggregate change and published asynchronously by the outbox relay.
+-- Rows are deleted once published, so the table only holds pending events.
+CREATE TABLE IF NOT EXISTS outbox (
+    seq             BIGSERIAL   PRIMARY KEY,
+    id              UUID        NOT NULL UNIQUE,
+    aggregate_type  TEXT        NOT NULL,
+    aggregate_id    TEXT        NOT NULL,
+    event_type      TEXT        NOT NULL,
+    payload         JSONB       NOT NULL,
+    occurred_at     TIMESTAMPTZ NOT NULL,
+    attempts        INTEGER     NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    locked_until    TIMESTAMPTZ,
+    last_error      TEXT
+);
+
+-- Pending events per aggregate, in publishing order
+CREATE INDEX IF NOT EXISTS idx_outbox_aggregate ON outbox (aggregate_id, seq);
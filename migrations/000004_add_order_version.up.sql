@@ -0,0 +1,3 @@
+-- Optimistic locking: every save bumps the version and only applies if the
+-- stored version is still the one the order was loaded at
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
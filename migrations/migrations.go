@@ -0,0 +1,12 @@
+// Package migrations embeds the SQL schema migrations so they ship inside the binary.
+//
+// Files are named <version>_<description>.up.sql / .down.sql and are applied
+// in lexical order of their version prefix.
+package migrations
+
+import "embed"
+
+// FS contains all *.sql migration files.
+//
+//go:embed *.sql
+var FS embed.FS
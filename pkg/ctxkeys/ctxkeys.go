@@ -0,0 +1,51 @@
+// Package ctxkeys defines the context keys shared by the HTTP middleware, the
+// logger and the application, so a value stored by one package is found by
+// the others (e.g., the request ID set by middleware.RequestID is added to
+// logs by logger.WithContext).
+package ctxkeys
+
+import "context"
+
+// Key is the type of the shared context keys, to avoid collisions with keys
+// defined by other packages.
+type Key string
+
+const (
+	// RequestID is the context key for the request ID.
+	RequestID Key = "request_id"
+
+	// UserID is the context key for the authenticated user ID.
+	UserID Key = "user_id"
+
+	// TenantID is the context key for the tenant ID.
+	TenantID Key = "tenant_id"
+
+	// ClientIP is the context key for the real client IP.
+	ClientIP Key = "client_ip"
+)
+
+// With returns a copy of ctx carrying value under key.
+//
+// Parameters:
+//   - ctx: The parent context
+//   - key: The shared key
+//   - value: The value to store
+//
+// Returns:
+//   - context.Context: The context carrying the value
+func With(ctx context.Context, key Key, value string) context.Context {
+	return context.WithValue(ctx, key, value)
+}
+
+// Get returns the value stored under key.
+//
+// Parameters:
+//   - ctx: The context
+//   - key: The shared key
+//
+// Returns:
+//   - string: The value, or empty string if not found
+func Get(ctx context.Context, key Key) string {
+	value, _ := ctx.Value(key).(string)
+	return value
+}
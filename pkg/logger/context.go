@@ -0,0 +1,128 @@
+package logger
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/hapkiduki/order-go/pkg/ctxkeys"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ContextExtractor returns the log fields (key-value pairs) found in ctx, or
+// nil when ctx does not carry its value.
+type ContextExtractor func(ctx context.Context) []any
+
+// namedExtractor is a registered ContextExtractor.
+type namedExtractor struct {
+	name    string
+	extract ContextExtractor
+}
+
+// extractors are applied by WithContext in registration order.
+var extractors struct {
+	mu   sync.RWMutex
+	list []namedExtractor
+}
+
+// init registers the built-in extractors.
+func init() {
+	RegisterContextExtractor("request_id", StringExtractor("request_id", ctxkeys.RequestID))
+	RegisterContextExtractor("user_id", StringExtractor("user_id", ctxkeys.UserID))
+	RegisterContextExtractor("tenant_id", StringExtractor("tenant_id", ctxkeys.TenantID))
+	RegisterContextExtractor("client_ip", StringExtractor("client_ip", ctxkeys.ClientIP))
+	RegisterContextExtractor("trace", traceExtractor)
+}
+
+// RegisterContextExtractor adds an extractor applied by WithContext (and so by
+// every port.Logger adapter built on Logger). Registering a name again
+// replaces the previous extractor, keeping its position.
+//
+// Built-in extractors: request_id, user_id, tenant_id, client_ip and trace
+// (trace_id and span_id).
+//
+// Parameters:
+//   - name: The extractor name
+//   - extract: The extractor
+func RegisterContextExtractor(name string, extract ContextExtractor) {
+	extractors.mu.Lock()
+	defer extractors.mu.Unlock()
+
+	i := slices.IndexFunc(extractors.list, func(e namedExtractor) bool { return e.name == name })
+	if i >= 0 {
+		extractors.list[i].extract = extract
+		return
+	}
+	extractors.list = append(extractors.list, namedExtractor{name: name, extract: extract})
+}
+
+// StringExtractor returns an extractor that logs the string stored under
+// key as field.
+//
+// Parameters:
+//   - field: The log field name
+//   - key: The context key
+//
+// Returns:
+//   - ContextExtractor: The extractor
+func StringExtractor(field string, key ctxkeys.Key) ContextExtractor {
+	return func(ctx context.Context) []any {
+		if value := ctxkeys.Get(ctx, key); value != "" {
+			return []any{field, value}
+		}
+		return nil
+	}
+}
+
+// ContextFields returns the fields of every registered extractor for ctx.
+//
+// Parameters:
+//   - ctx: The context to extract values from
+//
+// Returns:
+//   - []any: The key-value pairs found
+func ContextFields(ctx context.Context) []any {
+	extractors.mu.RLock()
+	defer extractors.mu.RUnlock()
+
+	var fields []any
+	for _, e := range extractors.list {
+		fields = append(fields, e.extract(ctx)...)
+	}
+	return fields
+}
+
+// ContextFieldSet holds the context fields added by WithContext. They are
+// logged inline, as top-level fields, but cores can tell them apart from the
+// other fields by this type (e.g., the recorder of package loggertest).
+type ContextFieldSet []zapcore.Field
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (s ContextFieldSet) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range s {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+// contextField returns the field logging the context fields (key-value
+// pairs) inline.
+func contextField(contextFields []any) zapcore.Field {
+	set := make(ContextFieldSet, 0, len(contextFields)/2)
+	for i := 0; i+1 < len(contextFields); i += 2 {
+		key, _ := contextFields[i].(string)
+		set = append(set, zap.Any(key, contextFields[i+1]))
+	}
+	return zap.Inline(set)
+}
+
+// traceExtractor logs the trace and span IDs of the current span.
+func traceExtractor(ctx context.Context) []any {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return []any{"trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String()}
+}
@@ -0,0 +1,247 @@
+package logger
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelState describes the global level or a named logger override.
+type LevelState struct {
+	// Name is the logger name ("" for the global level)
+	Name string
+
+	// Level is the current level
+	Level string
+
+	// ExpiresAt is when the level reverts (zero if it is permanent)
+	ExpiresAt time.Time
+
+	// RevertTo is the level restored at ExpiresAt ("" when a named override
+	// is removed, so the logger follows the global level again)
+	RevertTo string
+}
+
+// runtimeLevels are the levels SetLevelFor accepts: the ones configuration
+// validation allows. zap also parses "" (info), dpanic, panic and fatal, which
+// would let a runtime change silence every error.
+var runtimeLevels = map[string]zapcore.Level{
+	"debug": zapcore.DebugLevel,
+	"info":  zapcore.InfoLevel,
+	"warn":  zapcore.WarnLevel,
+	"error": zapcore.ErrorLevel,
+}
+
+// parseRuntimeLevel parses one of runtimeLevels.
+func parseRuntimeLevel(level string) (zapcore.Level, error) {
+	parsed, ok := runtimeLevels[level]
+	if !ok {
+		return 0, fmt.Errorf("unknown level %q (want debug, info, warn or error)", level)
+	}
+	return parsed, nil
+}
+
+// levels holds the global level and the per-logger overrides shared by a
+// logger and every logger derived from it.
+type levels struct {
+	// global is the level of loggers without an override
+	global zap.AtomicLevel
+
+	// overrides maps logger names to their level. It is replaced as a whole
+	// so logging reads it without locking.
+	overrides atomic.Pointer[map[string]zapcore.Level]
+
+	// mu serializes changes and guards reverts
+	mu      sync.Mutex
+	reverts map[string]*revert
+}
+
+// revert is a pending automatic level revert.
+type revert struct {
+	timer     *time.Timer
+	expiresAt time.Time
+
+	// level is restored at expiresAt; nil removes the named override
+	level *zapcore.Level
+}
+
+// newLevels creates the shared levels with the given global level.
+func newLevels(global zap.AtomicLevel) *levels {
+	ls := &levels{global: global, reverts: map[string]*revert{}}
+	ls.overrides.Store(&map[string]zapcore.Level{})
+	return ls
+}
+
+// enabled reports whether the logger called name logs at level l. An override
+// for "orders" also applies to "orders.repository", unless it has its own.
+func (ls *levels) enabled(name string, l zapcore.Level) bool {
+	overrides := *ls.overrides.Load()
+	if len(overrides) > 0 {
+		for n := name; n != ""; n = parentName(n) {
+			if level, ok := overrides[n]; ok {
+				return l >= level
+			}
+		}
+	}
+	return ls.global.Enabled(l)
+}
+
+// set changes the level of name ("" for the global level). With a positive
+// ttl the previous level is restored after ttl; otherwise the change is
+// permanent and cancels any pending revert.
+func (ls *levels) set(name string, level zapcore.Level, ttl time.Duration) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	pending, hasPending := ls.reverts[name]
+	if hasPending {
+		pending.timer.Stop()
+		delete(ls.reverts, name)
+	}
+
+	if ttl > 0 {
+		// Successive temporary changes restore the last permanent level
+		restore := ls.current(name)
+		if hasPending {
+			restore = pending.level
+		}
+		r := &revert{expiresAt: time.Now().Add(ttl), level: restore}
+		r.timer = time.AfterFunc(ttl, func() { ls.expire(name, r) })
+		ls.reverts[name] = r
+	}
+
+	ls.apply(name, &level)
+}
+
+// reset removes the override of name, or reverts a temporary global level now.
+func (ls *levels) reset(name string) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	pending, hasPending := ls.reverts[name]
+	if hasPending {
+		pending.timer.Stop()
+		delete(ls.reverts, name)
+	}
+
+	switch {
+	case name != "":
+		ls.apply(name, nil)
+	case hasPending:
+		ls.apply(name, pending.level)
+	}
+}
+
+// expire applies a revert when its TTL is over, unless it was replaced.
+func (ls *levels) expire(name string, r *revert) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if ls.reverts[name] != r {
+		return
+	}
+	delete(ls.reverts, name)
+	ls.apply(name, r.level)
+}
+
+// current returns the level set for name, or nil if name has no override.
+// Callers must hold mu.
+func (ls *levels) current(name string) *zapcore.Level {
+	if name == "" {
+		level := ls.global.Level()
+		return &level
+	}
+	if level, ok := (*ls.overrides.Load())[name]; ok {
+		return &level
+	}
+	return nil
+}
+
+// apply sets the level of name, removing a named override when level is nil.
+// Callers must hold mu.
+func (ls *levels) apply(name string, level *zapcore.Level) {
+	if name == "" {
+		if level != nil {
+			ls.global.SetLevel(*level)
+		}
+		return
+	}
+
+	overrides := maps.Clone(*ls.overrides.Load())
+	if level == nil {
+		delete(overrides, name)
+	} else {
+		overrides[name] = *level
+	}
+	ls.overrides.Store(&overrides)
+}
+
+// states returns the global level followed by the overrides, sorted by name.
+func (ls *levels) states() []LevelState {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	overrides := *ls.overrides.Load()
+	states := make([]LevelState, 0, len(overrides)+1)
+	for _, name := range append([]string{""}, slices.Sorted(maps.Keys(overrides))...) {
+		state := LevelState{Name: name, Level: ls.current(name).String()}
+		if r, ok := ls.reverts[name]; ok {
+			state.ExpiresAt = r.expiresAt
+			if r.level != nil {
+				state.RevertTo = r.level.String()
+			}
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// parentName returns the name of the parent logger ("a.b" -> "a", "a" -> "").
+func parentName(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+// levelCore gates a core with the level of a named logger.
+type levelCore struct {
+	zapcore.Core
+	levels *levels
+	name   string
+}
+
+// Enabled implements zapcore.LevelEnabler.
+func (c *levelCore) Enabled(l zapcore.Level) bool {
+	return c.levels.enabled(c.name, l)
+}
+
+// With implements zapcore.Core.
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), levels: c.levels, name: c.name}
+}
+
+// Check implements zapcore.Core.
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// withLevel returns a zap option gating the logger called name with ls.
+func withLevel(ls *levels, name string) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if lc, ok := core.(*levelCore); ok {
+			core = lc.Core
+		}
+		return &levelCore{Core: core, levels: ls, name: name}
+	})
+}
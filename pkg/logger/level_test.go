@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newLevelLogger(t *testing.T) (*Logger, *observer.ObservedLogs) {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := DefaultConfig()
+	cfg.Core = core
+	l, err := new(cfg)
+	require.NoError(t, err)
+	return l, logs
+}
+
+// levelOf returns the lowest level the logger called name writes.
+func levelOf(l *Logger, name string) string {
+	for _, level := range []zapcore.Level{zapcore.DebugLevel, zapcore.InfoLevel, zapcore.WarnLevel, zapcore.ErrorLevel} {
+		if l.levels.enabled(name, level) {
+			return level.String()
+		}
+	}
+	return "none"
+}
+
+func TestSetLevelFor_Levels(t *testing.T) {
+	l, _ := newLevelLogger(t)
+
+	for _, level := range []string{"debug", "info", "warn", "error"} {
+		require.NoError(t, l.SetLevelFor("", level, 0))
+		assert.Equal(t, level, l.Level())
+	}
+
+	// zap parses these, but they are not runtime levels
+	for _, level := range []string{"", "dpanic", "panic", "fatal", "DEBUG", "verbose"} {
+		err := l.SetLevelFor("orders", level, 0)
+		assert.ErrorContains(t, err, "want debug, info, warn or error", "level %q", level)
+	}
+	assert.Equal(t, "error", l.Level(), "the current level is kept")
+	assert.Len(t, l.Levels(), 1, "no override added")
+}
+
+func TestSetLevelFor_Inheritance(t *testing.T) {
+	l, logs := newLevelLogger(t)
+	require.NoError(t, l.SetLevel("warn"))
+	require.NoError(t, l.SetLevelFor("orders", "debug", 0))
+	require.NoError(t, l.SetLevelFor("orders.repository.cache", "error", 0))
+
+	assert.Equal(t, "warn", levelOf(l, ""))
+	assert.Equal(t, "warn", levelOf(l, "outbox"))
+	assert.Equal(t, "debug", levelOf(l, "orders"))
+	assert.Equal(t, "debug", levelOf(l, "orders.repository"), "from the parent")
+	assert.Equal(t, "error", levelOf(l, "orders.repository.cache"), "its own level")
+	assert.Equal(t, "warn", levelOf(l, "ordersx"), "not a child")
+
+	l.Named("orders").Named("repository").Debug("query")
+	l.Named("outbox").Debug("poll")
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "orders.repository", logs.All()[0].LoggerName)
+}
+
+func TestResetLevel(t *testing.T) {
+	l, _ := newLevelLogger(t)
+	require.NoError(t, l.SetLevelFor("orders", "debug", 0))
+
+	l.ResetLevel("orders")
+	assert.Equal(t, "info", levelOf(l, "orders"), "follows the global level again")
+	assert.Len(t, l.Levels(), 1)
+
+	// A permanent global level cannot be reset, a temporary one reverts now
+	require.NoError(t, l.SetLevel("warn"))
+	l.ResetLevel("")
+	assert.Equal(t, "warn", l.Level())
+
+	require.NoError(t, l.SetLevelFor("", "debug", time.Hour))
+	l.ResetLevel("")
+	assert.Equal(t, "warn", l.Level())
+	assert.True(t, l.Levels()[0].ExpiresAt.IsZero())
+}
+
+func TestSetLevelFor_TTL(t *testing.T) {
+	l, _ := newLevelLogger(t)
+	require.NoError(t, l.SetLevelFor("", "debug", 20*time.Millisecond))
+	require.NoError(t, l.SetLevelFor("orders", "error", 20*time.Millisecond))
+
+	states := l.Levels()
+	require.Len(t, states, 2)
+	assert.Equal(t, LevelState{Name: "", Level: "debug", ExpiresAt: states[0].ExpiresAt, RevertTo: "info"}, states[0])
+	assert.Equal(t, "", states[1].RevertTo, "the override is removed on expiry")
+	assert.False(t, states[1].ExpiresAt.IsZero())
+
+	assert.Eventually(t, func() bool {
+		return l.Level() == "info" && len(l.Levels()) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "info", levelOf(l, "orders"))
+}
+
+func TestSetLevelFor_SuccessiveTTLs(t *testing.T) {
+	l, _ := newLevelLogger(t)
+
+	// A second temporary change restores the last permanent level, not the first change
+	require.NoError(t, l.SetLevelFor("", "warn", time.Hour))
+	require.NoError(t, l.SetLevelFor("", "debug", 20*time.Millisecond))
+	assert.Equal(t, "info", l.Levels()[0].RevertTo)
+	assert.Eventually(t, func() bool { return l.Level() == "info" }, time.Second, 5*time.Millisecond)
+
+	// A permanent change cancels the pending revert
+	require.NoError(t, l.SetLevelFor("", "debug", 20*time.Millisecond))
+	require.NoError(t, l.SetLevel("error"))
+	time.Sleep(40 * time.Millisecond)
+	assert.Equal(t, "error", l.Level())
+}
@@ -4,33 +4,37 @@
 //
 // 12-Factor App compliance:
 //   - XI. Logs: Treat logs as event streams
-//   - Output to stdout, no log file management
+//   - Output to stdout by default; stderr and rotated files are opt-in
 //   - Structured logging format (JSON) for easy parsing
 package logger
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/hapkiduki/order-go/pkg/ctxkeys"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-// contextKey is a custom type for context keys.
-type contextKey string
-
+// Context keys read by WithContext; they are the shared keys of package ctxkeys.
 const (
 	// RequestIDKey is the context key for the request ID.
-	RequestIDKey contextKey = "request_id"
+	RequestIDKey = ctxkeys.RequestID
 
 	// UserIDKey is the context key for the user ID.
-	UserIDKey contextKey = "user_id"
+	UserIDKey = ctxkeys.UserID
 )
 
 // Logger is the application logger interface implementation.
 type Logger struct {
 	zap    *zap.Logger
 	sugar  *zap.SugaredLogger
+	levels *levels
+	name   string
 	fields []interface{}
 }
 
@@ -42,18 +46,58 @@ type Config struct {
 	// Format is the output format (json, console).
 	Format string
 
+	// Output is where logs are written: stdout, stderr or a file path.
+	// Empty means stdout.
+	Output string
+
+	// Rotation contains rotation and retention settings for file outputs
+	Rotation RotationConfig
+
+	// Sinks replace Output with several outputs, each with its own format and
+	// minimum level (e.g., console on stdout plus JSON in a file). Each file
+	// can be the output of one sink only. Optional.
+	Sinks []SinkConfig
+
+	// Sampling drops repeated entries of the same message and level once they
+	// exceed a rate, e.g. in a hot loop (nil, or an Initial of 0, logs every entry)
+	Sampling *SamplingConfig
+
+	// Redaction is the policy applied to every entry before it is encoded, so
+	// PII and credentials cannot be logged by accident (nil disables it)
+	Redaction *RedactionConfig
+
+	// Core replaces Output and Sinks: entries are written to it after levels,
+	// sampling and redaction (e.g., the recorder of package loggertest). Optional.
+	Core zapcore.Core
+
 	// Development enables development mode (more verbose)
 	Development bool
 }
 
+// SinkConfig contains the configuration of one log output.
+type SinkConfig struct {
+	// Output is stdout, stderr or a file path
+	Output string
+
+	// Format is the output format (json, console); empty uses Config.Format
+	Format string
+
+	// Level is the minimum level written to this sink; empty writes every
+	// entry enabled by Config.Level, which stays the minimum for all sinks
+	Level string
+}
+
 // DefaultConfig returns the default logger configuration.
 //
 // Returns:
 //   - Config: default logger configuration
 func DefaultConfig() Config {
+	redaction := DefaultRedactionConfig()
 	return Config{
 		Level:       "info",
 		Format:      "json",
+		Output:      "stdout",
+		Redaction:   &redaction,
 		Development: false,
 	}
 }
@@ -67,37 +111,69 @@ func DefaultConfig() Config {
 //   - *Logger: configured logger instance
 //   - error: Any error during initialization
 func new(cfg Config) (*Logger, error) {
-	// Parse log level
-	level := zapcore.InfoLevel
+	// Parse log level; it can be changed later with SetLevel
+	level := zap.NewAtomicLevel()
 	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
 		return nil, err
 	}
 
-	// configure encoder
-	encoderConfig := zap.NewProductionEncoderConfig()
-	encoderConfig.TimeKey = "timestamp"
-	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-	encoderConfig.EncodeLevel = zapcore.LowercaseColorLevelEncoder
+	var redact *redactor
+	if cfg.Redaction != nil {
+		var err error
+		if redact, err = newRedactor(*cfg.Redaction); err != nil {
+			return nil, err
+		}
+	}
 
-	var encoder zapcore.Encoder
-	if cfg.Format == "console" {
-		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
-		encoder = zapcore.NewConsoleEncoder(encoderConfig)
-	} else {
-		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	sinks := cfg.Sinks
+	if len(sinks) == 0 {
+		sinks = []SinkConfig{{Output: cfg.Output}}
+	}
+	if cfg.Core != nil {
+		sinks = nil
 	}
 
-	// Create core
-	core := zapcore.NewCore(
-		encoder,
-		zapcore.AddSync(os.Stdout),
-		level,
-	)
+	if err := checkSinkFiles(sinks); err != nil {
+		return nil, err
+	}
+
+	// Create one core per sink; the logger level gates all of them
+	cores := make([]zapcore.Core, 0, len(sinks))
+	for _, sink := range sinks {
+		if sink.Format == "" {
+			sink.Format = cfg.Format
+		}
+		core, err := newCore(sink, cfg.Rotation)
+		if err != nil {
+			return nil, err
+		}
+
+		// Each sink redacts on its own, so sink levels keep working
+		if redact != nil {
+			core = &redactCore{Core: core, redactor: redact}
+		}
+		cores = append(cores, core)
+	}
+	if cfg.Core != nil {
+		core := cfg.Core
+		if redact != nil {
+			core = &redactCore{Core: core, redactor: redact}
+		}
+		cores = append(cores, core)
+	}
+	core := zapcore.NewTee(cores...)
+
+	// Summaries of dropped entries bypass the sampler
+	levels := newLevels(level)
+	if cfg.Sampling != nil && cfg.Sampling.Initial > 0 && cfg.Sampling.Tick > 0 {
+		core = newSampler(core, *cfg.Sampling, zap.New(core, withLevel(levels, "")))
+	}
 
 	// Build logger
 	opts := []zap.Option{
 		zap.AddCaller(),
 		zap.AddCallerSkip(1),
+		withLevel(levels, ""),
 	}
 
 	if cfg.Development {
@@ -107,11 +183,94 @@ func new(cfg Config) (*Logger, error) {
 	zapLogger := zap.New(core, opts...)
 
 	return &Logger{
-		zap:   zapLogger,
-		sugar: zapLogger.Sugar(),
+		zap:    zapLogger,
+		sugar:  zapLogger.Sugar(),
+		levels: levels,
 	}, nil
 }
 
+// checkSinkFiles rejects sinks writing to the same file: each sink rotates
+// its file on its own, so they would write over each other's entries.
+//
+// Parameters:
+//   - sinks: The sinks of the logger
+//
+// Returns:
+//   - error: If two sinks share a file path
+func checkSinkFiles(sinks []SinkConfig) error {
+	seen := make(map[string]string, len(sinks))
+	for _, sink := range sinks {
+		switch sink.Output {
+		case "", "stdout", "stderr":
+			continue
+		}
+		path := filepath.Clean(sink.Output)
+		if other, ok := seen[path]; ok {
+			return fmt.Errorf("log sinks %s and %s write to the same file", other, sink.Output)
+		}
+		seen[path] = sink.Output
+	}
+	return nil
+}
+
+// newCore creates the zap core writing to a sink.
+//
+// Parameters:
+//   - sink: The sink configuration (Format already defaulted)
+//   - rotation: Rotation settings, used when the sink is a file
+//
+// Returns:
+//   - zapcore.Core: The core
+//   - error: If the sink level is invalid or its file cannot be opened
+func newCore(sink SinkConfig, rotation RotationConfig) (zapcore.Core, error) {
+	// The logger level is checked first, so sinks only add their own minimum
+	minimum := zapcore.DebugLevel
+	if sink.Level != "" {
+		if err := minimum.UnmarshalText([]byte(sink.Level)); err != nil {
+			return nil, fmt.Errorf("log sink %s: %w", sink.Output, err)
+		}
+	}
+
+	// Colors are only for terminals, never for files
+	var output zapcore.WriteSyncer
+	color := true
+	switch sink.Output {
+	case "", "stdout":
+		output = zapcore.AddSync(os.Stdout)
+	case "stderr":
+		output = zapcore.AddSync(os.Stderr)
+	default:
+		file, err := openRotatingFile(sink.Output, rotation)
+		if err != nil {
+			return nil, fmt.Errorf("log sink %s: %w", sink.Output, err)
+		}
+		output = file
+		color = false
+	}
+
+	// configure encoder
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "timestamp"
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
+	if color {
+		encoderConfig.EncodeLevel = zapcore.LowercaseColorLevelEncoder
+	}
+
+	var encoder zapcore.Encoder
+	if sink.Format == "console" {
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		if color {
+			encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	} else {
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	}
+
+	return zapcore.NewCore(encoder, output, minimum), nil
+}
+
 // MustNew creates a new Logger and panics on error.
 //
 // Parameters:
@@ -184,11 +343,14 @@ func (l *Logger) With(keysAndValues ...interface{}) *Logger {
 	return &Logger{
 		zap:    l.zap,
 		sugar:  l.sugar,
+		levels: l.levels,
+		name:   l.name,
 		fields: append(l.fields, keysAndValues...),
 	}
 }
 
 // WithContext return a logger with context information (e.g., request ID, user ID, etc.).
+// The fields come from the registered extractors (see RegisterContextExtractor).
 //
 // Parameters:
 //   - ctx: the context to extract values from
@@ -196,20 +358,17 @@ func (l *Logger) With(keysAndValues ...interface{}) *Logger {
 // Returns:
 //   - Logger: new logger with context fields
 func (l *Logger) WithContext(ctx context.Context) *Logger {
-	fields := make([]any, 0, len(l.fields)+4)
+	fields := make([]any, 0, len(l.fields)+1)
 	fields = append(fields, l.fields...)
-
-	if requestID := ctx.Value(RequestIDKey); requestID != nil {
-		fields = append(fields, "request_id", requestID)
-	}
-
-	if userID := ctx.Value(UserIDKey); userID != nil {
-		fields = append(fields, "user_id", userID)
+	if contextFields := ContextFields(ctx); len(contextFields) > 0 {
+		fields = append(fields, contextField(contextFields))
 	}
 
 	return &Logger{
 		zap:    l.zap,
 		sugar:  l.sugar,
+		levels: l.levels,
+		name:   l.name,
 		fields: fields,
 	}
 }
@@ -223,7 +382,72 @@ func (l *Logger) Sync() error {
 	return l.zap.Sync()
 }
 
-// Named returns a named logger
+// SetLevel changes the global minimum log level at runtime.
+// The change applies to this logger and every logger derived from it
+// (With, WithContext, Named), since they share the same level, except for
+// named loggers with their own level (see SetLevelFor). It cancels a
+// pending automatic revert of the global level.
+//
+// Parameters:
+//   - level: The new level (debug, info, warn, error)
+//
+// Returns:
+//   - error: If the level is not debug, info, warn or error (the current
+//     level is kept)
+func (l *Logger) SetLevel(level string) error {
+	return l.SetLevelFor("", level, 0)
+}
+
+// SetLevelFor changes the level of the loggers called name (see Named) and
+// of their children, or the global level when name is empty. With a positive
+// ttl the change is temporary: the previous level is restored after ttl, e.g.
+// to debug production for ten minutes during an incident.
+//
+// Parameters:
+//   - name: The logger name (e.g., "outbox"), or "" for the global level
+//   - level: The new level (debug, info, warn, error)
+//   - ttl: How long the level lasts (0 for a permanent change)
+//
+// Returns:
+//   - error: If the level is not debug, info, warn or error (the current
+//     level is kept)
+func (l *Logger) SetLevelFor(name, level string, ttl time.Duration) error {
+	parsed, err := parseRuntimeLevel(level)
+	if err != nil {
+		return err
+	}
+	l.levels.set(name, parsed, ttl)
+	return nil
+}
+
+// ResetLevel removes the level of the loggers called name, so they follow
+// the global level again. With an empty name, a temporary global level is
+// reverted immediately.
+//
+// Parameters:
+//   - name: The logger name, or "" for the global level
+func (l *Logger) ResetLevel(name string) {
+	l.levels.reset(name)
+}
+
+// Level returns the current global minimum log level.
+//
+// Returns:
+//   - string: The level name (e.g., "info")
+func (l *Logger) Level() string {
+	return l.levels.global.Level().String()
+}
+
+// Levels returns the global level followed by the named logger levels.
+//
+// Returns:
+//   - []LevelState: The levels and their pending reverts
+func (l *Logger) Levels() []LevelState {
+	return l.levels.states()
+}
+
+// Named returns a named logger. Names of nested loggers are joined with dots
+// (e.g., "orders.repository"); their level can be set with SetLevelFor.
 //
 // Parameters:
 //   - name: The logger name (will be added to log output)
@@ -231,9 +455,17 @@ func (l *Logger) Sync() error {
 // Returns:
 //   - *Logger: A named logger
 func (l *Logger) Named(name string) *Logger {
+	fullName := name
+	if l.name != "" {
+		fullName = l.name + "." + name
+	}
+
+	named := l.zap.Named(name).WithOptions(withLevel(l.levels, fullName))
 	return &Logger{
-		zap:    l.zap.Named(name),
-		sugar:  l.zap.Named(name).Sugar(),
+		zap:    named,
+		sugar:  named.Sugar(),
+		levels: l.levels,
+		name:   fullName,
 		fields: l.fields,
 	}
 }
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_SinkFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	tests := []struct {
+		name  string
+		sinks []SinkConfig
+		err   string
+	}{
+		{
+			name:  "same file",
+			sinks: []SinkConfig{{Output: path}, {Output: path, Level: "error"}},
+			err:   "write to the same file",
+		},
+		{
+			name:  "same file through another path",
+			sinks: []SinkConfig{{Output: path}, {Output: filepath.Join(dir, ".", "app.log")}},
+			err:   "write to the same file",
+		},
+		{
+			name:  "different files",
+			sinks: []SinkConfig{{Output: path}, {Output: filepath.Join(dir, "error.log"), Level: "error"}},
+		},
+		{
+			name:  "standard streams can be shared",
+			sinks: []SinkConfig{{Output: "stdout"}, {Output: "stdout", Format: "console"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Sinks = tt.sinks
+
+			l, err := new(cfg)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, l)
+		})
+	}
+}
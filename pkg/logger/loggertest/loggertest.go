@@ -0,0 +1,288 @@
+// Package loggertest provides loggers that record their entries in memory, so
+// tests can assert what the code under test logs.
+//
+// Example usage:
+//
+//	rec := loggertest.New()
+//	handler := middleware.RequestID(middleware.Recoverer(logging.New(rec.Logger()), nil)(panicking))
+//	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+//
+//	entry := rec.RequireEntry(t, "error", "Panic recovered")
+//	entry.AssertHasField(t, "request_id")
+//	entry.AssertHasField(t, "stack")
+package loggertest
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hapkiduki/order-go/pkg/logger"
+	"go.uber.org/zap/zapcore"
+)
+
+// Entry is a recorded log entry.
+type Entry struct {
+	// Level is the entry level (debug, info, warn, error)
+	Level string
+
+	// Logger is the name of the logger (see logger.Logger.Named), empty for the root logger
+	Logger string
+
+	// Message is the log message
+	Message string
+
+	// Fields are the fields of the entry, except the context fields
+	Fields map[string]any
+
+	// Context are the fields added by WithContext (request_id, trace_id, ...)
+	Context map[string]any
+
+	// Time is when the entry was logged
+	Time time.Time
+}
+
+// Field returns the value of a field or context field. Values are recorded as
+// zap encodes them: integers are int64, errors are their message, etc.
+//
+// Parameters:
+//   - key: The field key
+//
+// Returns:
+//   - any: The value
+//   - bool: Whether the entry has the field
+func (e Entry) Field(key string) (any, bool) {
+	if value, ok := e.Fields[key]; ok {
+		return value, true
+	}
+	value, ok := e.Context[key]
+	return value, ok
+}
+
+// AssertField checks that the entry has a field (or context field) equal to
+// want. Values are compared by their fmt representation, so an int matches
+// the int64 recorded for it.
+//
+// Parameters:
+//   - t: The test
+//   - key: The field key
+//   - want: The expected value
+func (e Entry) AssertField(t testing.TB, key string, want any) {
+	t.Helper()
+
+	got, ok := e.Field(key)
+	if !ok {
+		t.Errorf("log entry %q has no field %q: %s", e.Message, key, e)
+		return
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("log entry %q: field %q = %v, want %v", e.Message, key, got, want)
+	}
+}
+
+// AssertHasField checks that the entry has a non-empty field (or context field).
+//
+// Parameters:
+//   - t: The test
+//   - key: The field key
+func (e Entry) AssertHasField(t testing.TB, key string) {
+	t.Helper()
+
+	if got, ok := e.Field(key); !ok || fmt.Sprint(got) == "" {
+		t.Errorf("log entry %q has no field %q: %s", e.Message, key, e)
+	}
+}
+
+// String returns the entry in a readable form for test failures.
+func (e Entry) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", e.Level, e.Message)
+	if e.Logger != "" {
+		fmt.Fprintf(&b, " logger=%s", e.Logger)
+	}
+	for _, fields := range []map[string]any{e.Context, e.Fields} {
+		for _, key := range slices.Sorted(maps.Keys(fields)) {
+			fmt.Fprintf(&b, " %s=%v", key, fields[key])
+		}
+	}
+	return b.String()
+}
+
+// Recorder records the entries of its loggers.
+type Recorder struct {
+	logger *logger.Logger
+
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// New creates a Recorder. Its loggers log every level and apply the default
+// redaction policy, so tests see the fields as production would log them.
+//
+// Returns:
+//   - *Recorder: The recorder
+func New() *Recorder {
+	r := &Recorder{}
+	redaction := logger.DefaultRedactionConfig()
+	r.logger = logger.MustNew(logger.Config{
+		Level:     "debug",
+		Format:    "json",
+		Redaction: &redaction,
+		Core:      &recorderCore{recorder: r},
+	})
+	return r
+}
+
+// Logger returns a *logger.Logger recording to r. Code taking a port.Logger
+// gets it through its adapter (e.g., logging.New(rec.Logger())).
+//
+// Returns:
+//   - *logger.Logger: The logger
+func (r *Recorder) Logger() *logger.Logger {
+	return r.logger
+}
+
+// Entries returns the recorded entries, oldest first.
+//
+// Returns:
+//   - []Entry: The entries
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.entries)
+}
+
+// Filter returns the entries with a level and message; an empty level or
+// message matches any.
+//
+// Parameters:
+//   - level: The level (debug, info, warn, error)
+//   - message: The exact message
+//
+// Returns:
+//   - []Entry: The matching entries, oldest first
+func (r *Recorder) Filter(level, message string) []Entry {
+	var matched []Entry
+	for _, e := range r.Entries() {
+		if (level == "" || e.Level == level) && (message == "" || e.Message == message) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// Reset discards the recorded entries.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+// RequireEntry returns the last entry with a level and message, and stops the
+// test when there is none.
+//
+// Parameters:
+//   - t: The test
+//   - level: The level; empty matches any
+//   - message: The exact message; empty matches any
+//
+// Returns:
+//   - Entry: The last matching entry
+func (r *Recorder) RequireEntry(t testing.TB, level, message string) Entry {
+	t.Helper()
+
+	matched := r.Filter(level, message)
+	if len(matched) == 0 {
+		t.Fatalf("no [%s] %q log entry; recorded:\n%s", level, message, r.dump())
+	}
+	return matched[len(matched)-1]
+}
+
+// AssertNoEntry checks that no entry with a level and message was recorded.
+//
+// Parameters:
+//   - t: The test
+//   - level: The level; empty matches any
+//   - message: The exact message; empty matches any
+func (r *Recorder) AssertNoEntry(t testing.TB, level, message string) {
+	t.Helper()
+
+	if matched := r.Filter(level, message); len(matched) > 0 {
+		t.Errorf("unexpected [%s] %q log entry: %s", level, message, matched[0])
+	}
+}
+
+// dump lists the recorded entries for test failures.
+func (r *Recorder) dump() string {
+	entries := r.Entries()
+	if len(entries) == 0 {
+		return "  (none)"
+	}
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = "  " + e.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// record adds an entry.
+func (r *Recorder) record(e Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e)
+}
+
+// recorderCore is the zapcore.Core of the Recorder loggers. Levels are
+// checked by the logger, so it accepts every entry.
+type recorderCore struct {
+	recorder *Recorder
+	fields   []zapcore.Field
+}
+
+// Enabled implements zapcore.Core.
+func (c *recorderCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// With implements zapcore.Core.
+func (c *recorderCore) With(fields []zapcore.Field) zapcore.Core {
+	return &recorderCore{recorder: c.recorder, fields: append(slices.Clip(c.fields), fields...)}
+}
+
+// Check implements zapcore.Core.
+func (c *recorderCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return checked.AddCore(entry, c)
+}
+
+// Write implements zapcore.Core.
+func (c *recorderCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	// Context fields are marked by WithContext, the others are plain fields
+	values := zapcore.NewMapObjectEncoder()
+	contextValues := zapcore.NewMapObjectEncoder()
+	for _, f := range slices.Concat(c.fields, fields) {
+		if _, ok := f.Interface.(logger.ContextFieldSet); ok && f.Type == zapcore.InlineMarshalerType {
+			f.AddTo(contextValues)
+			continue
+		}
+		f.AddTo(values)
+	}
+
+	c.recorder.record(Entry{
+		Level:   entry.Level.String(),
+		Logger:  entry.LoggerName,
+		Message: entry.Message,
+		Fields:  values.Fields,
+		Context: contextValues.Fields,
+		Time:    entry.Time,
+	})
+	return nil
+}
+
+// Sync implements zapcore.Core.
+func (c *recorderCore) Sync() error {
+	return nil
+}
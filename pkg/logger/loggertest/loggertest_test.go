@@ -0,0 +1,147 @@
+package loggertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/hapkiduki/order-go/pkg/ctxkeys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeT records the failures reported to it.
+type fakeT struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Fatalf(format string, args ...any) {
+	t.Errorf(format, args...)
+	t.fatal = true
+	runtime.Goexit()
+}
+
+// run calls f in its own goroutine, so a Fatalf only stops f.
+func (t *fakeT) run(f func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+	<-done
+}
+
+func TestRecorder_Entries(t *testing.T) {
+	rec := New()
+	log := rec.Logger()
+
+	log.Debug("debug entry")
+	log.Named("outbox").With("component", "relay").Info("claimed", "count", 3)
+	log.Warn("slow", "error", errors.New("timeout"))
+
+	entries := rec.Entries()
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, "debug", entries[0].Level)
+	assert.Equal(t, "debug entry", entries[0].Message)
+	assert.Empty(t, entries[0].Logger)
+	assert.False(t, entries[0].Time.IsZero())
+
+	assert.Equal(t, "info", entries[1].Level)
+	assert.Equal(t, "outbox", entries[1].Logger)
+	assert.Equal(t, map[string]any{"component": "relay", "count": int64(3)}, entries[1].Fields)
+
+	assert.Equal(t, "timeout", entries[2].Fields["error"], "errors are recorded as their message")
+}
+
+func TestRecorder_ContextFields(t *testing.T) {
+	rec := New()
+	ctx := context.WithValue(context.Background(), ctxkeys.RequestID, "req-1")
+
+	rec.Logger().WithContext(ctx).Info("handled", "status", 200)
+
+	entry := rec.RequireEntry(t, "info", "handled")
+	assert.Equal(t, map[string]any{"request_id": "req-1"}, entry.Context)
+	assert.Equal(t, map[string]any{"status": int64(200)}, entry.Fields)
+
+	// Field looks in both
+	value, ok := entry.Field("request_id")
+	assert.True(t, ok)
+	assert.Equal(t, "req-1", value)
+	entry.AssertField(t, "status", 200)
+}
+
+func TestRecorder_Redaction(t *testing.T) {
+	rec := New()
+
+	rec.Logger().Info("login", "password", "hunter2", "email", "jane@example.com")
+
+	entry := rec.RequireEntry(t, "info", "login")
+	_, ok := entry.Field("password")
+	assert.False(t, ok, "dropped by the default policy")
+	assert.NotContains(t, entry.Fields["email"], "jane@example.com")
+}
+
+func TestRecorder_FilterAndReset(t *testing.T) {
+	rec := New()
+	log := rec.Logger()
+
+	log.Info("a", "n", 1)
+	log.Error("a", "n", 2)
+	log.Info("b")
+	log.Info("a", "n", 3)
+
+	assert.Len(t, rec.Filter("info", "a"), 2)
+	assert.Len(t, rec.Filter("", "a"), 3)
+	assert.Len(t, rec.Filter("info", ""), 3)
+
+	// RequireEntry returns the last match
+	rec.RequireEntry(t, "", "a").AssertField(t, "n", 3)
+
+	rec.Reset()
+	assert.Empty(t, rec.Entries())
+	rec.AssertNoEntry(t, "", "")
+}
+
+func TestRecorder_Concurrent(t *testing.T) {
+	rec := New()
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() { rec.Logger().Info("entry") })
+	}
+	wg.Wait()
+
+	assert.Len(t, rec.Entries(), 10)
+}
+
+func TestRecorder_ReportsFailures(t *testing.T) {
+	rec := New()
+	rec.Logger().Info("handled", "status", 500)
+	entry := rec.Entries()[0]
+
+	ft := &fakeT{}
+	entry.AssertField(ft, "status", 200)
+	entry.AssertField(ft, "missing", 1)
+	entry.AssertHasField(ft, "request_id")
+	rec.AssertNoEntry(ft, "info", "handled")
+	assert.Len(t, ft.errors, 4)
+	assert.False(t, ft.fatal)
+
+	ft = &fakeT{}
+	ft.run(func() { rec.RequireEntry(ft, "error", "handled") })
+	assert.True(t, ft.fatal)
+	require.Len(t, ft.errors, 1)
+	assert.Contains(t, ft.errors[0], "[info] handled status=500", "lists the recorded entries")
+}
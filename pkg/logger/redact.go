@@ -0,0 +1,539 @@
+package logger
+
+import (
+	"cmp"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Redaction modes.
+const (
+	// RedactMask replaces the value with a mask keeping a non-sensitive part
+	// (e.g., the last 4 digits of a card number)
+	RedactMask = "mask"
+
+	// RedactHash replaces the value with a keyed hash, so entries about the
+	// same value can still be correlated
+	RedactHash = "hash"
+
+	// RedactDrop removes the field (or the matched text from a message)
+	RedactDrop = "drop"
+
+	// RedactOff disables a rule (e.g., one of the defaults)
+	RedactOff = "off"
+)
+
+// Value patterns detected in string fields and messages.
+const (
+	// PatternEmail matches email addresses
+	PatternEmail = "email"
+
+	// PatternPAN matches card numbers (13-19 digits passing the Luhn check)
+	PatternPAN = "pan"
+
+	// PatternBearer matches bearer tokens (e.g., an Authorization header value)
+	PatternBearer = "bearer"
+)
+
+// redactedValue replaces masked values without a non-sensitive part.
+const redactedValue = "[REDACTED]"
+
+// RedactionConfig contains the redaction policy applied to every entry
+// before it is encoded.
+type RedactionConfig struct {
+	// Keys maps field keys to the mode applied to their value, whatever it
+	// is (e.g., "authorization": "drop"). Keys are split into lowercase
+	// segments at '_', '-', '.' and camelCase boundaries, and match the field
+	// keys holding the same segments in a row: "token" also matches
+	// "access_token", "X-Auth-Token" and "refreshToken", but not "tokens".
+	// When several keys match, the one with the most segments wins (e.g.,
+	// "csrf_token": "off" exempts it from "token").
+	Keys map[string]string
+
+	// Patterns maps value patterns (PatternEmail, PatternPAN, PatternBearer)
+	// to the mode applied to the text they match in strings and messages
+	Patterns map[string]string
+
+	// HashKey keys the hashes of RedactHash, so they cannot be reversed by
+	// hashing guesses (e.g., known emails). Optional but recommended.
+	HashKey string
+}
+
+// DefaultRedactionConfig returns the default redaction policy: credentials
+// are dropped, emails, card numbers and bearer tokens are masked.
+//
+// Returns:
+//   - RedactionConfig: Default policy
+func DefaultRedactionConfig() RedactionConfig {
+	return RedactionConfig{
+		Keys: map[string]string{
+			"api_key":       RedactDrop,
+			"apikey":        RedactDrop,
+			"authorization": RedactDrop,
+			"cookie":        RedactDrop,
+			"password":      RedactDrop,
+			"secret":        RedactDrop,
+			"token":         RedactDrop,
+		},
+		Patterns: map[string]string{
+			PatternEmail:  RedactMask,
+			PatternPAN:    RedactMask,
+			PatternBearer: RedactMask,
+		},
+	}
+}
+
+var (
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	panPattern    = regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`)
+	bearerPattern = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`)
+)
+
+// valuePattern detects one kind of sensitive text.
+type valuePattern struct {
+	mode string
+
+	// mayMatch is a cheap test run before the regular expression
+	mayMatch func(s string) bool
+	regexp   *regexp.Regexp
+
+	// valid filters regexp matches (e.g., the Luhn check); nil accepts all
+	valid func(match string) bool
+
+	// mask masks a match
+	mask func(match string) string
+}
+
+// keyRule applies a mode to the fields whose key contains its segments.
+type keyRule struct {
+	segments []string
+	mode     string
+}
+
+// redactor applies a RedactionConfig.
+type redactor struct {
+	// keys are the key rules, most specific first
+	keys     []keyRule
+	patterns []valuePattern
+	hashKey  []byte
+}
+
+// newRedactor validates cfg and prepares it for use.
+//
+// Parameters:
+//   - cfg: The redaction policy
+//
+// Returns:
+//   - *redactor: The redactor, or nil when no rule is enabled
+//   - error: If a mode or pattern is unknown
+func newRedactor(cfg RedactionConfig) (*redactor, error) {
+	if len(cfg.Keys) == 0 && len(cfg.Patterns) == 0 {
+		return nil, nil
+	}
+
+	r := &redactor{hashKey: []byte(cfg.HashKey)}
+	active := false
+	for key, mode := range cfg.Keys {
+		if err := checkMode(mode); err != nil {
+			return nil, fmt.Errorf("redaction key %s: %w", key, err)
+		}
+		segments := keySegments(key)
+		if len(segments) == 0 {
+			return nil, fmt.Errorf("redaction key %q has no letters or digits", key)
+		}
+		r.keys = append(r.keys, keyRule{segments: segments, mode: mode})
+		active = active || mode != RedactOff
+	}
+	if !active {
+		// Off rules only exempt keys from other rules
+		r.keys = nil
+	}
+	slices.SortFunc(r.keys, func(a, b keyRule) int {
+		return cmp.Or(
+			cmp.Compare(len(b.segments), len(a.segments)),
+			cmp.Compare(modeStrictness[b.mode], modeStrictness[a.mode]),
+			slices.Compare(a.segments, b.segments),
+		)
+	})
+
+	// Bearer tokens first: they may contain text that looks like other patterns
+	for _, name := range []string{PatternBearer, PatternEmail, PatternPAN} {
+		mode, ok := cfg.Patterns[name]
+		if !ok {
+			continue
+		}
+		if err := checkMode(mode); err != nil {
+			return nil, fmt.Errorf("redaction pattern %s: %w", name, err)
+		}
+		if mode == RedactOff {
+			continue
+		}
+
+		switch name {
+		case PatternBearer:
+			r.patterns = append(r.patterns, valuePattern{
+				mode:     mode,
+				mayMatch: func(s string) bool { return strings.Contains(strings.ToLower(s), "bearer") },
+				regexp:   bearerPattern,
+				mask:     func(string) string { return "Bearer " + redactedValue },
+			})
+		case PatternEmail:
+			r.patterns = append(r.patterns, valuePattern{
+				mode:     mode,
+				mayMatch: func(s string) bool { return strings.Contains(s, "@") },
+				regexp:   emailPattern,
+				mask:     maskEmail,
+			})
+		case PatternPAN:
+			r.patterns = append(r.patterns, valuePattern{
+				mode:     mode,
+				mayMatch: hasDigits(13),
+				regexp:   panPattern,
+				valid:    luhn,
+				mask:     maskPAN,
+			})
+		}
+	}
+	for name := range cfg.Patterns {
+		if name != PatternBearer && name != PatternEmail && name != PatternPAN {
+			return nil, fmt.Errorf("unknown redaction pattern %q (want %s, %s or %s)", name, PatternEmail, PatternPAN, PatternBearer)
+		}
+	}
+	if len(r.keys) == 0 && len(r.patterns) == 0 {
+		return nil, nil
+	}
+	return r, nil
+}
+
+// checkMode validates a redaction mode.
+func checkMode(mode string) error {
+	switch mode {
+	case RedactMask, RedactHash, RedactDrop, RedactOff:
+		return nil
+	}
+	return fmt.Errorf("unknown redaction mode %q (want %s, %s, %s or %s)", mode, RedactMask, RedactHash, RedactDrop, RedactOff)
+}
+
+// modeStrictness ranks the modes, to pick between equally specific key rules.
+var modeStrictness = map[string]int{RedactOff: 0, RedactHash: 1, RedactMask: 2, RedactDrop: 3}
+
+// keySegments splits a field key into lowercase segments at '_', '-', '.'
+// and camelCase boundaries (e.g., "X-Api-Key" -> [x api key], "apiKey" ->
+// [api key]).
+func keySegments(key string) []string {
+	var (
+		segments []string
+		segment  strings.Builder
+		prev     rune
+	)
+	flush := func() {
+		if segment.Len() > 0 {
+			segments = append(segments, segment.String())
+			segment.Reset()
+		}
+	}
+	for _, c := range key {
+		switch {
+		case c == '_' || c == '-' || c == '.':
+			flush()
+		case unicode.IsUpper(c) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			flush()
+			segment.WriteRune(unicode.ToLower(c))
+		default:
+			segment.WriteRune(unicode.ToLower(c))
+		}
+		prev = c
+	}
+	flush()
+	return segments
+}
+
+// keyMode returns the mode of the most specific key rule matching key, and
+// whether the key is redacted.
+func (r *redactor) keyMode(key string) (string, bool) {
+	if len(r.keys) == 0 {
+		return "", false
+	}
+
+	segments := keySegments(key)
+	for _, rule := range r.keys {
+		if containsSegments(segments, rule.segments) {
+			return rule.mode, rule.mode != RedactOff
+		}
+	}
+	return "", false
+}
+
+// containsSegments reports whether sub is a run of consecutive segments of s.
+func containsSegments(s, sub []string) bool {
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if slices.Equal(s[i:i+len(sub)], sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// fields returns fields with the policy applied, and whether a field changed.
+// The slice is only copied when a field changes.
+func (r *redactor) fields(fields []zapcore.Field) ([]zapcore.Field, bool) {
+	var out []zapcore.Field
+	for i, f := range fields {
+		redacted, keep, changed := r.field(f)
+		if out == nil {
+			if !changed {
+				continue
+			}
+			out = make([]zapcore.Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+		if keep {
+			out = append(out, redacted)
+		}
+	}
+	if out == nil {
+		return fields, false
+	}
+	return out, true
+}
+
+// field applies the policy to one field. It reports whether the field is
+// kept and whether it changed.
+func (r *redactor) field(f zapcore.Field) (zapcore.Field, bool, bool) {
+	if mode, ok := r.keyMode(f.Key); ok {
+		switch mode {
+		case RedactDrop:
+			return f, false, true
+		case RedactHash:
+			return zap.String(f.Key, r.hash(fieldString(f))), true, true
+		default:
+			return zap.String(f.Key, redactedValue), true, true
+		}
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		if s, changed := r.text(f.String); changed {
+			return zap.String(f.Key, s), true, true
+		}
+	case zapcore.ByteStringType, zapcore.StringerType, zapcore.ErrorType:
+		if s, changed := r.text(fieldString(f)); changed {
+			return zap.String(f.Key, s), true, true
+		}
+	case zapcore.ReflectType:
+		if v, changed := r.value(f.Interface); changed {
+			return zap.Any(f.Key, v), true, true
+		}
+	case zapcore.InlineMarshalerType:
+		if set, ok := f.Interface.(ContextFieldSet); ok {
+			if redacted, changed := r.fields(set); changed {
+				return zap.Inline(ContextFieldSet(redacted)), true, true
+			}
+		}
+	case zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType:
+		// Nested values (e.g., slog groups) are encoded to be inspected
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		if v, changed := r.value(enc.Fields[f.Key]); changed {
+			return zap.Any(f.Key, v), true, true
+		}
+	}
+	return f, true, false
+}
+
+// value applies the policy to maps with string keys (e.g., http.Header) and
+// string slices, recursively. Other values are returned unchanged.
+func (r *redactor) value(v any) (any, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return r.text(rv.String())
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return v, false
+		}
+		out := make(map[string]any, rv.Len())
+		changed := false
+		for iter := rv.MapRange(); iter.Next(); {
+			key := iter.Key().String()
+			if mode, ok := r.keyMode(key); ok {
+				changed = true
+				switch mode {
+				case RedactDrop:
+					continue
+				case RedactHash:
+					out[key] = r.hash(fmt.Sprint(iter.Value().Interface()))
+				default:
+					out[key] = redactedValue
+				}
+				continue
+			}
+			item, itemChanged := r.value(iter.Value().Interface())
+			out[key] = item
+			changed = changed || itemChanged
+		}
+		if !changed {
+			return v, false
+		}
+		return out, true
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() != reflect.String && rv.Type().Elem().Kind() != reflect.Interface {
+			return v, false
+		}
+		out := make([]any, rv.Len())
+		changed := false
+		for i := range rv.Len() {
+			item, itemChanged := r.value(rv.Index(i).Interface())
+			out[i] = item
+			changed = changed || itemChanged
+		}
+		if !changed {
+			return v, false
+		}
+		return out, true
+	}
+	return v, false
+}
+
+// text applies the value patterns to s.
+func (r *redactor) text(s string) (string, bool) {
+	changed := false
+	for _, p := range r.patterns {
+		if !p.mayMatch(s) {
+			continue
+		}
+		s = p.regexp.ReplaceAllStringFunc(s, func(match string) string {
+			if p.valid != nil && !p.valid(match) {
+				return match
+			}
+			changed = true
+			switch p.mode {
+			case RedactDrop:
+				return ""
+			case RedactHash:
+				return r.hash(match)
+			default:
+				return p.mask(match)
+			}
+		})
+	}
+	return s, changed
+}
+
+// hash returns a short keyed hash of s.
+func (r *redactor) hash(s string) string {
+	mac := hmac.New(sha256.New, r.hashKey)
+	mac.Write([]byte(s))
+	return "sha256:" + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// fieldString returns the value of a field as a string.
+func fieldString(f zapcore.Field) string {
+	switch f.Type {
+	case zapcore.StringType:
+		return f.String
+	case zapcore.ByteStringType:
+		return string(f.Interface.([]byte))
+	case zapcore.StringerType:
+		return f.Interface.(fmt.Stringer).String()
+	case zapcore.ErrorType:
+		return f.Interface.(error).Error()
+	}
+
+	// Encode any other field on its own to get its value
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	return fmt.Sprint(enc.Fields[f.Key])
+}
+
+// maskEmail keeps the first character and the domain (j***@example.com).
+func maskEmail(email string) string {
+	at := strings.LastIndexByte(email, '@')
+	return email[:1] + "***" + email[at:]
+}
+
+// maskPAN keeps the last 4 digits (************1111).
+func maskPAN(pan string) string {
+	digits := onlyDigits(pan)
+	return strings.Repeat("*", len(digits)-4) + digits[len(digits)-4:]
+}
+
+// luhn reports whether the digits of s pass the Luhn checksum.
+func luhn(s string) bool {
+	digits := onlyDigits(s)
+	sum := 0
+	for i := range len(digits) {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// onlyDigits returns the digits of s.
+func onlyDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// hasDigits returns a test for strings with at least n digits.
+func hasDigits(n int) func(string) bool {
+	return func(s string) bool {
+		count := 0
+		for i := range len(s) {
+			if s[i] >= '0' && s[i] <= '9' {
+				count++
+				if count >= n {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// redactCore applies a redactor to the entries of a core.
+type redactCore struct {
+	zapcore.Core
+	redactor *redactor
+}
+
+// With implements zapcore.Core.
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	fields, _ = c.redactor.fields(fields)
+	return &redactCore{Core: c.Core.With(fields), redactor: c.redactor}
+}
+
+// Check implements zapcore.Core. It adds itself so Write redacts the entry.
+func (c *redactCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write implements zapcore.Core.
+func (c *redactCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message, _ = c.redactor.text(entry.Message)
+	fields, _ = c.redactor.fields(fields)
+	return c.Core.Write(entry, fields)
+}
@@ -0,0 +1,231 @@
+package logger
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func newTestRedactor(t *testing.T, cfg RedactionConfig) *redactor {
+	t.Helper()
+
+	r, err := newRedactor(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	return r
+}
+
+// redactString applies r to a string field; "<dropped>" stands for a dropped field.
+func redactString(r *redactor, key, value string) string {
+	f, keep, _ := r.field(zap.String(key, value))
+	if !keep {
+		return "<dropped>"
+	}
+	return f.String
+}
+
+func TestKeySegments(t *testing.T) {
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"token", []string{"token"}},
+		{"access_token", []string{"access", "token"}},
+		{"X-Api-Key", []string{"x", "api", "key"}},
+		{"db.password", []string{"db", "password"}},
+		{"refreshToken", []string{"refresh", "token"}},
+		{"stripeAPIKey", []string{"stripe", "apikey"}},
+		{"oauth2Token", []string{"oauth2", "token"}},
+		{"APIKEY", []string{"apikey"}},
+		{"__token--", []string{"token"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			assert.Equal(t, tt.want, keySegments(tt.key))
+		})
+	}
+}
+
+func TestRedactor_DefaultKeys(t *testing.T) {
+	r := newTestRedactor(t, DefaultRedactionConfig())
+
+	tests := []struct {
+		key      string
+		redacted bool
+	}{
+		{"password", true},
+		{"Password", true},
+		{"db_password", true},
+		{"access_token", true},
+		{"X-Auth-Token", true},
+		{"refreshToken", true},
+		{"client.secret", true},
+		{"api_key", true},
+		{"X-Api-Key", true},
+		{"apiKey", true},
+		{"apikey", true},
+		{"stripe_api_key", true},
+		{"Set-Cookie", true},
+		{"Proxy-Authorization", true},
+
+		// Segments must match whole
+		{"tokens", false},
+		{"secretary", false},
+		{"key", false},
+		{"api", false},
+		{"passwordless", false},
+		{"order_id", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got := redactString(r, tt.key, "value")
+			if tt.redacted {
+				assert.Equal(t, "<dropped>", got)
+			} else {
+				assert.Equal(t, "value", got)
+			}
+		})
+	}
+}
+
+func TestRedactor_KeyModes(t *testing.T) {
+	r := newTestRedactor(t, RedactionConfig{
+		Keys: map[string]string{
+			"token":         RedactDrop,
+			"csrf_token":    RedactOff,
+			"customer_id":   RedactHash,
+			"card.number":   RedactMask,
+			"session":       RedactMask,
+			"session_token": RedactHash,
+		},
+		HashKey: "key",
+	})
+
+	assert.Equal(t, "<dropped>", redactString(r, "access_token", "t"))
+	assert.Equal(t, "t", redactString(r, "csrf_token", "t"), "a more specific off rule exempts the key")
+	assert.Equal(t, "t", redactString(r, "X-CSRF-Token", "t"))
+	assert.Equal(t, r.hash("c-1"), redactString(r, "customerId", "c-1"))
+	assert.Equal(t, redactedValue, redactString(r, "card_number", "4111"))
+	assert.Equal(t, r.hash("s"), redactString(r, "user_session_token", "s"), "the rule with the most segments wins")
+
+	// Non-string values are redacted by key too
+	f, keep, changed := r.field(zap.Int("customer_id", 42))
+	assert.True(t, keep)
+	assert.True(t, changed)
+	assert.Equal(t, r.hash("42"), f.String)
+}
+
+func TestRedactor_EquallySpecificKeys(t *testing.T) {
+	r := newTestRedactor(t, RedactionConfig{
+		Keys: map[string]string{"user": RedactHash, "token": RedactDrop, "id": RedactMask},
+	})
+
+	// user_token matches user and token: the strictest mode wins
+	assert.Equal(t, "<dropped>", redactString(r, "user_token", "t"))
+	assert.Equal(t, redactedValue, redactString(r, "user_id", "u"))
+}
+
+func TestRedactor_OffOnly(t *testing.T) {
+	r, err := newRedactor(RedactionConfig{Keys: map[string]string{"token": RedactOff}})
+	require.NoError(t, err)
+	assert.Nil(t, r, "no rule is enabled")
+}
+
+func TestRedactor_Maps(t *testing.T) {
+	r := newTestRedactor(t, DefaultRedactionConfig())
+
+	header := http.Header{
+		"X-Api-Key":     {"k"},
+		"Authorization": {"Bearer abc"},
+		"X-Request-Id":  {"req-1"},
+		"X-Forwarded":   {"for=jane@example.com"},
+	}
+	f, keep, changed := r.field(zap.Any("headers", header))
+	require.True(t, keep)
+	require.True(t, changed)
+
+	redacted := f.Interface.(map[string]any)
+	assert.NotContains(t, redacted, "X-Api-Key")
+	assert.NotContains(t, redacted, "Authorization")
+	assert.Equal(t, []string{"req-1"}, redacted["X-Request-Id"])
+	assert.Equal(t, []any{"for=j***@example.com"}, redacted["X-Forwarded"])
+}
+
+func TestRedactor_Patterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		mode    string
+		in      string
+		want    string
+	}{
+		{"email mask", PatternEmail, RedactMask, "sent to jane.doe@example.com", "sent to j***@example.com"},
+		{"email drop", PatternEmail, RedactDrop, "sent to jane@example.com", "sent to "},
+		{"pan mask", PatternPAN, RedactMask, "card 4111 1111 1111 1111 declined", "card ************1111 declined"},
+		{"pan fails luhn", PatternPAN, RedactMask, "order 4111111111111112", "order 4111111111111112"},
+		{"pan too short", PatternPAN, RedactMask, "phone 555123456789", "phone 555123456789"},
+		{"bearer mask", PatternBearer, RedactMask, "header bearer eyJhbGciOi.J9.x", "header Bearer [REDACTED]"},
+		{"no match", PatternEmail, RedactMask, "nothing to hide", "nothing to hide"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRedactor(t, RedactionConfig{Patterns: map[string]string{tt.pattern: tt.mode}})
+
+			got, changed := r.text(tt.in)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want != tt.in, changed)
+		})
+	}
+}
+
+func TestRedactor_PatternHash(t *testing.T) {
+	r := newTestRedactor(t, RedactionConfig{Patterns: map[string]string{PatternEmail: RedactHash}, HashKey: "key"})
+	other := newTestRedactor(t, RedactionConfig{Patterns: map[string]string{PatternEmail: RedactHash}, HashKey: "other"})
+
+	first, _ := r.text("jane@example.com")
+	second, _ := r.text("jane@example.com")
+	assert.Equal(t, first, second, "hashes correlate entries")
+	assert.Regexp(t, `^sha256:[0-9a-f]{16}$`, first)
+
+	keyed, _ := other.text("jane@example.com")
+	assert.NotEqual(t, first, keyed, "hashes depend on the key")
+}
+
+func TestRedactor_PatternsInFields(t *testing.T) {
+	r := newTestRedactor(t, DefaultRedactionConfig())
+
+	f, _, changed := r.field(zap.Error(errors.New("no user jane@example.com")))
+	assert.True(t, changed)
+	assert.Equal(t, "no user j***@example.com", f.String)
+
+	f, _, changed = r.field(zap.Strings("emails", []string{"a@example.com", "none"}))
+	assert.True(t, changed)
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	assert.Equal(t, []any{"a***@example.com", "none"}, enc.Fields["emails"])
+}
+
+func TestNewRedactor_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  RedactionConfig
+		want string
+	}{
+		{"unknown key mode", RedactionConfig{Keys: map[string]string{"token": "erase"}}, `redaction key token: unknown redaction mode "erase"`},
+		{"key without segments", RedactionConfig{Keys: map[string]string{"_-.": RedactDrop}}, `redaction key "_-." has no letters or digits`},
+		{"unknown pattern", RedactionConfig{Patterns: map[string]string{"ssn": RedactMask}}, `unknown redaction pattern "ssn"`},
+		{"unknown pattern mode", RedactionConfig{Patterns: map[string]string{PatternPAN: "erase"}}, `redaction pattern pan: unknown redaction mode "erase"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newRedactor(tt.cfg)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the timestamp added to rotated file names
+// (e.g., app-2026-01-02T15-04-05.000.log). It sorts chronologically.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// rotateRetryDelay is how long a file keeps growing after a failed rotation
+// before the next attempt, so a persistent failure is not retried (and
+// reported) on every entry.
+const rotateRetryDelay = time.Minute
+
+// rename renames the active file on rotation (replaced in tests).
+var rename = os.Rename
+
+// RotationConfig contains log file rotation and retention settings.
+// The zero value never rotates and keeps the file growing.
+type RotationConfig struct {
+	// MaxSizeMB rotates the file before it grows beyond this size (0 disables)
+	MaxSizeMB int
+
+	// Interval rotates the file on UTC boundaries of this period, e.g. 24h
+	// rotates at midnight UTC (0 disables)
+	Interval time.Duration
+
+	// MaxBackups is the number of rotated files kept (0 keeps all)
+	MaxBackups int
+
+	// MaxAge deletes rotated files older than this (0 keeps them)
+	MaxAge time.Duration
+}
+
+// rotatingFile is a log file that rotates itself by size and time.
+// Rotated files are renamed with a timestamp next to the active file.
+type rotatingFile struct {
+	mu     sync.Mutex
+	path   string
+	config RotationConfig
+	file   *os.File
+	size   int64
+
+	// rotateAt is when the time-based rotation is due (zero if disabled)
+	rotateAt time.Time
+
+	// retryAt delays the next rotation after a failed one
+	retryAt time.Time
+}
+
+// openRotatingFile opens (or creates) the log file at path, creating its
+// directory if needed, and appends to it.
+//
+// Parameters:
+//   - path: The log file path
+//   - config: Rotation and retention settings
+//
+// Returns:
+//   - *rotatingFile: The file, ready for writing
+//   - error: If the file cannot be opened
+func openRotatingFile(path string, config RotationConfig) (*rotatingFile, error) {
+	f := &rotatingFile{path: path, config: config}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write implements io.Writer, rotating the file first when it is due.
+// A failed rotation does not lose the entry: it is written to the current
+// file, and the rotation error is returned so zap reports it.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var rotateErr error
+	if f.due(len(p)) {
+		if rotateErr = f.rotate(); rotateErr != nil {
+			f.retryAt = time.Now().Add(rotateRetryDelay)
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if err != nil {
+		return n, err
+	}
+	return n, rotateErr
+}
+
+// Sync implements zapcore.WriteSyncer.
+func (f *rotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.file.Sync()
+}
+
+// Close closes the active file.
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.file.Close()
+}
+
+// due reports whether writing n more bytes requires a rotation first.
+func (f *rotatingFile) due(n int) bool {
+	if f.size == 0 || time.Now().Before(f.retryAt) {
+		return false
+	}
+	if f.config.MaxSizeMB > 0 && f.size+int64(n) > int64(f.config.MaxSizeMB)<<20 {
+		return true
+	}
+	return !f.rotateAt.IsZero() && !time.Now().Before(f.rotateAt)
+}
+
+// open opens the active file and computes the next time-based rotation.
+func (f *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+
+	// An existing file is rotated once its period is over, even across restarts
+	if f.config.Interval > 0 {
+		started := time.Now()
+		if f.size > 0 {
+			started = info.ModTime()
+		}
+		f.rotateAt = started.Truncate(f.config.Interval).Add(f.config.Interval)
+	}
+	return nil
+}
+
+// rotate renames the active file with a timestamp, opens a new one and
+// removes the backups beyond the retention limits. The active file is only
+// closed once the new one is open, so f.file stays usable on every error.
+func (f *rotatingFile) rotate() error {
+	ext := filepath.Ext(f.path)
+	backup := strings.TrimSuffix(f.path, ext) + "-" + time.Now().UTC().Format(backupTimeFormat) + ext
+
+	// A file deleted by hand has nothing left to rename: just start a new one
+	renamed := true
+	if err := rename(f.path, backup); err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("rotate log file: %w", err)
+		}
+		renamed = false
+	}
+
+	previous := f.file
+	if err := f.open(); err != nil {
+		// Give the file its name back and keep writing to it
+		if renamed {
+			os.Rename(backup, f.path)
+		}
+		return err
+	}
+	f.retryAt = time.Time{}
+
+	// Retention is best effort: a file that cannot be removed is retried next time
+	f.prune()
+
+	if err := previous.Close(); err != nil {
+		return fmt.Errorf("close rotated log file: %w", err)
+	}
+	return nil
+}
+
+// prune removes rotated files beyond MaxBackups or older than MaxAge.
+func (f *rotatingFile) prune() {
+	if f.config.MaxBackups <= 0 && f.config.MaxAge <= 0 {
+		return
+	}
+
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(filepath.Base(f.path), ext) + "-"
+	entries, err := os.ReadDir(filepath.Dir(f.path))
+	if err != nil {
+		return
+	}
+
+	type backup struct {
+		path      string
+		rotatedAt time.Time
+	}
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		rotatedAt, err := time.Parse(backupTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{filepath.Join(filepath.Dir(f.path), name), rotatedAt})
+	}
+
+	// Newest first
+	slices.SortFunc(backups, func(a, b backup) int {
+		return b.rotatedAt.Compare(a.rotatedAt)
+	})
+
+	cutoff := time.Now().Add(-f.config.MaxAge)
+	for i, b := range backups {
+		tooMany := f.config.MaxBackups > 0 && i >= f.config.MaxBackups
+		tooOld := f.config.MaxAge > 0 && b.rotatedAt.Before(cutoff)
+		if tooMany || tooOld {
+			os.Remove(b.path)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// halfMB is half the smallest MaxSizeMB: two writes fill a file.
+var halfMB = bytes.Repeat([]byte("x"), 1<<19)
+
+func openTestFile(t *testing.T, config RotationConfig) (*rotatingFile, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	f, err := openRotatingFile(path, config)
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+	return f, path
+}
+
+// backups returns the rotated files next to path, oldest first.
+func backups(t *testing.T, path string) []string {
+	t.Helper()
+
+	matches, err := filepath.Glob(strings.TrimSuffix(path, ".log") + "-*.log")
+	require.NoError(t, err)
+	return matches
+}
+
+func write(t *testing.T, f *rotatingFile, p []byte) {
+	t.Helper()
+
+	n, err := f.Write(p)
+	require.NoError(t, err)
+	require.Equal(t, len(p), n)
+}
+
+// replaceRename makes rotations use fn for the rest of the test.
+func replaceRename(t *testing.T, fn func(oldpath, newpath string) error) {
+	t.Helper()
+
+	rename = fn
+	t.Cleanup(func() { rename = os.Rename })
+}
+
+func TestRotatingFile_RotatesBySize(t *testing.T) {
+	f, path := openTestFile(t, RotationConfig{MaxSizeMB: 1})
+
+	write(t, f, halfMB)
+	write(t, f, halfMB)
+	assert.Empty(t, backups(t, path), "exactly MaxSizeMB fits")
+
+	write(t, f, []byte("next\n"))
+
+	rotated := backups(t, path)
+	require.Len(t, rotated, 1)
+	info, err := os.Stat(rotated[0])
+	require.NoError(t, err)
+	assert.Equal(t, int64(1<<20), info.Size())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "next\n", string(content))
+}
+
+func TestRotatingFile_RotatesByInterval(t *testing.T) {
+	f, path := openTestFile(t, RotationConfig{Interval: time.Hour})
+
+	write(t, f, []byte("first\n"))
+	f.rotateAt = time.Now().Add(-time.Second)
+	write(t, f, []byte("second\n"))
+
+	require.Len(t, backups(t, path), 1)
+	assert.True(t, f.rotateAt.After(time.Now()), "next rotation scheduled")
+}
+
+func TestRotatingFile_KeepsMaxBackups(t *testing.T) {
+	f, path := openTestFile(t, RotationConfig{MaxSizeMB: 1, MaxBackups: 2})
+
+	for range 4 {
+		write(t, f, halfMB)
+		write(t, f, halfMB)
+
+		// Backups are named by the millisecond
+		time.Sleep(2 * time.Millisecond)
+	}
+	write(t, f, halfMB)
+
+	assert.Len(t, backups(t, path), 2)
+}
+
+func TestRotatingFile_DeletesOldBackups(t *testing.T) {
+	f, path := openTestFile(t, RotationConfig{MaxSizeMB: 1, MaxAge: 24 * time.Hour})
+
+	dir := filepath.Dir(path)
+	old := filepath.Join(dir, "app-"+time.Now().Add(-48*time.Hour).UTC().Format(backupTimeFormat)+".log")
+	other := filepath.Join(dir, "app-notes.log")
+	for _, name := range []string{old, other} {
+		require.NoError(t, os.WriteFile(name, []byte("old\n"), 0o644))
+	}
+
+	write(t, f, halfMB)
+	write(t, f, halfMB)
+	write(t, f, halfMB)
+
+	assert.NoFileExists(t, old)
+	assert.FileExists(t, other, "not a backup")
+	assert.Len(t, backups(t, path), 2, "the new backup and app-notes.log")
+}
+
+func TestRotatingFile_RenameFails(t *testing.T) {
+	f, path := openTestFile(t, RotationConfig{MaxSizeMB: 1})
+	replaceRename(t, func(string, string) error { return errors.New("device busy") })
+
+	write(t, f, halfMB)
+	write(t, f, halfMB)
+
+	// The entry is written anyway, and the error reported once
+	n, err := f.Write([]byte("during\n"))
+	assert.ErrorContains(t, err, "device busy")
+	assert.Equal(t, len("during\n"), n)
+	write(t, f, []byte("after\n"))
+
+	// Once the retry delay is over, the rotation succeeds
+	replaceRename(t, os.Rename)
+	f.retryAt = time.Time{}
+	write(t, f, []byte("rotated\n"))
+
+	rotated := backups(t, path)
+	require.Len(t, rotated, 1)
+	content, err := os.ReadFile(rotated[0])
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(content), "during\nafter\n"))
+
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "rotated\n", string(content))
+}
+
+func TestRotatingFile_OpenFails(t *testing.T) {
+	f, path := openTestFile(t, RotationConfig{MaxSizeMB: 1})
+
+	// Something takes the path right after the rename, so the new file
+	// cannot be created
+	replaceRename(t, func(oldpath, newpath string) error {
+		if err := os.Rename(oldpath, newpath); err != nil {
+			return err
+		}
+		return os.Mkdir(oldpath, 0o755)
+	})
+
+	write(t, f, halfMB)
+	write(t, f, halfMB)
+	_, err := f.Write([]byte("during\n"))
+	assert.ErrorContains(t, err, "open log file")
+
+	// The directory is in the way of the rename back: the entries still go to
+	// the original file, under its backup name
+	write(t, f, []byte("after\n"))
+	rotated := backups(t, path)
+	require.Len(t, rotated, 1)
+	content, err := os.ReadFile(rotated[0])
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(content), "during\nafter\n"))
+}
+
+func TestRotatingFile_OpenFailsRenamesBack(t *testing.T) {
+	f, path := openTestFile(t, RotationConfig{MaxSizeMB: 1})
+
+	// The new file cannot be created while the path is a dangling symlink
+	// into a missing directory
+	dir := filepath.Dir(path)
+	replaceRename(t, func(oldpath, newpath string) error {
+		if err := os.Rename(oldpath, newpath); err != nil {
+			return err
+		}
+		return os.Symlink(filepath.Join(dir, "missing", "app.log"), oldpath)
+	})
+
+	write(t, f, halfMB)
+	write(t, f, halfMB)
+	_, err := f.Write([]byte("during\n"))
+	assert.ErrorContains(t, err, "open log file")
+	write(t, f, []byte("after\n"))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1<<20+len("during\nafter\n")), info.Size(), "still the active file")
+}
+
+func TestRotatingFile_ActiveFileDeleted(t *testing.T) {
+	f, path := openTestFile(t, RotationConfig{MaxSizeMB: 1})
+
+	write(t, f, halfMB)
+	write(t, f, halfMB)
+	require.NoError(t, os.Remove(path))
+
+	write(t, f, []byte("new\n"))
+
+	assert.Empty(t, backups(t, path))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new\n", string(content))
+}
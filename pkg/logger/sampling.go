@@ -0,0 +1,128 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SamplingConfig contains zap-style sampling settings: for each message and
+// level, the first Initial entries of every Tick are logged, then only every
+// Thereafter-th entry. Error entries (and above) are never sampled.
+type SamplingConfig struct {
+	// Initial is the number of entries per message and level logged each Tick
+	// (0 disables sampling)
+	Initial int
+
+	// Thereafter logs every Nth entry after Initial (0 drops them all)
+	Thereafter int
+
+	// Tick is the sampling period
+	Tick time.Duration
+
+	// SummaryInterval is how often the dropped entries are reported with a
+	// "Similar entries suppressed" entry per message (0 disables the summary)
+	SummaryInterval time.Duration
+}
+
+// samplingKey identifies entries sampled together.
+type samplingKey struct {
+	level   zapcore.Level
+	message string
+}
+
+// suppressedSummary counts the entries dropped by the sampler and reports
+// them periodically. Reports are written while logging, so no goroutine is
+// needed: counts wait until the next entry after the interval.
+type suppressedSummary struct {
+	logger   *zap.Logger
+	interval time.Duration
+
+	mu         sync.Mutex
+	counts     map[samplingKey]int
+	nextReport time.Time
+}
+
+// newSampler wraps core with a sampler reporting its dropped entries to
+// report, which must not be sampled itself. Entries at ErrorLevel and above
+// bypass the sampler: a burst of errors is exactly what must not be thinned.
+//
+// Parameters:
+//   - core: The core to sample
+//   - cfg: Sampling settings
+//   - report: The logger writing the summaries
+//
+// Returns:
+//   - zapcore.Core: The sampled core
+func newSampler(core zapcore.Core, cfg SamplingConfig, report *zap.Logger) zapcore.Core {
+	if cfg.SummaryInterval <= 0 {
+		return &unsampledErrors{
+			Core:   zapcore.NewSamplerWithOptions(core, cfg.Tick, cfg.Initial, cfg.Thereafter),
+			errors: core,
+		}
+	}
+
+	summary := &suppressedSummary{
+		logger:     report,
+		interval:   cfg.SummaryInterval,
+		counts:     map[samplingKey]int{},
+		nextReport: time.Now().Add(cfg.SummaryInterval),
+	}
+	return &unsampledErrors{
+		Core: zapcore.NewSamplerWithOptions(core, cfg.Tick, cfg.Initial, cfg.Thereafter,
+			zapcore.SamplerHook(summary.hook)),
+		errors: core,
+	}
+}
+
+// unsampledErrors sends entries below ErrorLevel to the sampler it embeds
+// and the others straight to errors, the core being sampled.
+type unsampledErrors struct {
+	zapcore.Core
+	errors zapcore.Core
+}
+
+// With implements zapcore.Core.
+func (c *unsampledErrors) With(fields []zapcore.Field) zapcore.Core {
+	return &unsampledErrors{
+		Core:   c.Core.With(fields),
+		errors: c.errors.With(fields),
+	}
+}
+
+// Check implements zapcore.Core.
+func (c *unsampledErrors) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level >= zapcore.ErrorLevel {
+		return c.errors.Check(entry, checked)
+	}
+	return c.Core.Check(entry, checked)
+}
+
+// hook implements the zapcore.SamplerHook callback.
+func (s *suppressedSummary) hook(entry zapcore.Entry, decision zapcore.SamplingDecision) {
+	now := time.Now()
+
+	s.mu.Lock()
+	if decision&zapcore.LogDropped != 0 {
+		s.counts[samplingKey{level: entry.Level, message: entry.Message}]++
+	}
+	if now.Before(s.nextReport) || len(s.counts) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	counts := s.counts
+	s.counts = map[samplingKey]int{}
+	s.nextReport = now.Add(s.interval)
+	s.mu.Unlock()
+
+	for key, n := range counts {
+		s.logger.Info("Similar entries suppressed",
+			zap.String("suppressed_message", key.message),
+			zap.String("suppressed_level", key.level.String()),
+			zap.Int("suppressed", n),
+			zap.Duration("interval", s.interval),
+		)
+	}
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newSampledLogger(t *testing.T, summaryInterval time.Duration) (*Logger, *observer.ObservedLogs) {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	cfg := DefaultConfig()
+	cfg.Core = core
+	cfg.Sampling = &SamplingConfig{
+		Initial:         2,
+		Thereafter:      0,
+		Tick:            time.Hour,
+		SummaryInterval: summaryInterval,
+	}
+	l, err := new(cfg)
+	require.NoError(t, err)
+	return l, logs
+}
+
+func TestSampler_ThinsRepeatedEntries(t *testing.T) {
+	l, logs := newSampledLogger(t, 0)
+
+	for range 10 {
+		l.Info("HTTP Request")
+		l.Warn("HTTP Request")
+	}
+
+	assert.Equal(t, 2, logs.FilterLevelExact(zapcore.InfoLevel).Len())
+	assert.Equal(t, 2, logs.FilterLevelExact(zapcore.WarnLevel).Len())
+}
+
+func TestSampler_NeverSamplesErrors(t *testing.T) {
+	for _, interval := range []time.Duration{0, time.Hour} {
+		l, logs := newSampledLogger(t, interval)
+
+		for range 10 {
+			l.Error("HTTP Request", "status", 502)
+		}
+		l.With("component", "relay").Error("Publish failed")
+		l.With("component", "relay").Error("Publish failed")
+		l.With("component", "relay").Error("Publish failed")
+
+		assert.Equal(t, 10, logs.FilterMessage("HTTP Request").Len(), "summary interval %s", interval)
+		published := logs.FilterMessage("Publish failed").All()
+		require.Len(t, published, 3, "through With")
+		assert.Equal(t, "relay", published[0].ContextMap()["component"])
+	}
+}
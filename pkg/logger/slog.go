@@ -0,0 +1,231 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// slogHandler is a slog.Handler writing to the zap core of a Logger, so
+// entries logged through log/slog share its sinks, format, levels, sampling
+// and redaction.
+type slogHandler struct {
+	core zapcore.Core
+	name string
+
+	// fields are the attributes added with WithAttrs, with a zap.Namespace
+	// for each group they belong to
+	fields []zapcore.Field
+
+	// groups are opened with WithGroup but have no attribute yet; empty
+	// groups are left out of the entries
+	groups []string
+}
+
+// NewSlogHandler returns a slog.Handler backed by l. Entries carry the fields
+// of l and the context fields of the registered extractors (see
+// RegisterContextExtractor) of the context passed to slog.
+//
+// Parameters:
+//   - l: The logger to write to
+//
+// Returns:
+//   - slog.Handler: The handler
+func NewSlogHandler(l *Logger) slog.Handler {
+	return &slogHandler{
+		core: l.sugar.With(l.fields...).Desugar().Core(),
+		name: l.name,
+	}
+}
+
+// Slog returns a slog.Logger backed by l (e.g., for slog.SetDefault, so
+// libraries using log/slog write to the same log stream).
+//
+// Returns:
+//   - *slog.Logger: The slog logger
+func (l *Logger) Slog() *slog.Logger {
+	return slog.New(NewSlogHandler(l))
+}
+
+// IsSlogHandler reports whether h was created by NewSlogHandler (or
+// Logger.Slog). Such handlers add the context fields of each entry
+// themselves, so adapters passing a context to them must not add them again.
+//
+// Parameters:
+//   - h: The handler
+//
+// Returns:
+//   - bool: Whether h is backed by a Logger
+func IsSlogHandler(h slog.Handler) bool {
+	_, ok := h.(*slogHandler)
+	return ok
+}
+
+// Enabled implements slog.Handler.
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.core.Enabled(zapLevel(level))
+}
+
+// Handle implements slog.Handler.
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	// Context fields stay at the top level, whatever the open groups
+	fields := make([]zapcore.Field, 0, 1+len(h.fields)+len(h.groups)+record.NumAttrs())
+	if contextFields := ContextFields(ctx); len(contextFields) > 0 {
+		fields = append(fields, contextField(contextFields))
+	}
+	fields = append(fields, h.fields...)
+
+	var attrs []zapcore.Field
+	record.Attrs(func(a slog.Attr) bool {
+		attrs = appendAttr(attrs, a)
+		return true
+	})
+	if len(attrs) > 0 {
+		fields = appendGroups(fields, h.groups)
+		fields = append(fields, attrs...)
+	}
+
+	entry := zapcore.Entry{
+		Level:      zapLevel(record.Level),
+		Time:       record.Time,
+		LoggerName: h.name,
+		Message:    record.Message,
+		Caller:     entryCaller(record.PC),
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	if checked := h.core.Check(entry, nil); checked != nil {
+		checked.Write(fields...)
+	}
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var added []zapcore.Field
+	for _, a := range attrs {
+		added = appendAttr(added, a)
+	}
+	if len(added) == 0 {
+		return h
+	}
+
+	fields := slices.Clip(appendGroups(slices.Clone(h.fields), h.groups))
+	return &slogHandler{
+		core:   h.core,
+		name:   h.name,
+		fields: append(fields, added...),
+	}
+}
+
+// WithGroup implements slog.Handler.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{
+		core:   h.core,
+		name:   h.name,
+		fields: h.fields,
+		groups: append(slices.Clip(h.groups), name),
+	}
+}
+
+// appendGroups appends a namespace for each group, so the fields after them
+// are nested.
+func appendGroups(fields []zapcore.Field, groups []string) []zapcore.Field {
+	for _, group := range groups {
+		fields = append(fields, zap.Namespace(group))
+	}
+	return fields
+}
+
+// appendAttr appends the zap field of a, following the slog.Handler rules:
+// empty attributes and groups are left out, groups without a key are inlined.
+func appendAttr(fields []zapcore.Field, a slog.Attr) []zapcore.Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return append(fields, zap.String(a.Key, a.Value.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(a.Key, a.Value.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(a.Key, a.Value.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(a.Key, a.Value.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(a.Key, a.Value.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(a.Key, a.Value.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.Time(a.Key, a.Value.Time()))
+	case slog.KindGroup:
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return fields
+		}
+		if a.Key == "" {
+			for _, attr := range attrs {
+				fields = appendAttr(fields, attr)
+			}
+			return fields
+		}
+		return append(fields, zap.Object(a.Key, slogGroup(attrs)))
+	}
+	return append(fields, zap.Any(a.Key, a.Value.Any()))
+}
+
+// slogGroup encodes the attributes of a slog group as a nested object.
+type slogGroup []slog.Attr
+
+// MarshalLogObject implements zapcore.ObjectMarshaler.
+func (g slogGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	var fields []zapcore.Field
+	for _, a := range g {
+		fields = appendAttr(fields, a)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+// zapLevel maps a slog level to the zap level it falls in.
+func zapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level >= slog.LevelError:
+		return zapcore.ErrorLevel
+	case level >= slog.LevelWarn:
+		return zapcore.WarnLevel
+	case level >= slog.LevelInfo:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
+
+// entryCaller returns the caller of a slog record.
+func entryCaller(pc uintptr) zapcore.EntryCaller {
+	if pc == 0 {
+		return zapcore.EntryCaller{}
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return zapcore.EntryCaller{
+		Defined:  true,
+		PC:       frame.PC,
+		File:     frame.File,
+		Line:     frame.Line,
+		Function: frame.Function,
+	}
+}